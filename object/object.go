@@ -28,6 +28,30 @@ type School struct {
 	Distance string `json:"distance" bson:"distance"`
 }
 
+// Match of the home's coordinates to its address
+type GeoMatch struct {
+	// "page" for the coordinates of the listing's page, or the name of the geocoder, e.g. "mapbox"
+	Source string `json:"source" bson:"source"`
+	// Status of the match: matched, partial, unmatched, or failed when geocoding it failed.
+	// It's empty if it's not geocoded yet. The coordinates are 0 when it's unmatched or failed.
+	Status string `json:"status" bson:"status"`
+	// Accuracy of the coordinates, e.g. rooftop, interpolated or postcode, which is empty if it's unknown
+	Accuracy string `json:"accuracy" bson:"accuracy"`
+	// Confidence of the match from 0 to 1, which is 0 if it's unknown
+	Relevance float32 `json:"relevance" bson:"relevance"`
+}
+
+// Event of the home's property history, e.g. it's listed or sold
+type PriceEvent struct {
+	// Date like "2024-05-03"
+	Date string `json:"date" bson:"date"`
+	// Type of the event: listed, price_change, pending, sold, delisted, rental or other
+	Event string  `json:"event" bson:"event"`
+	Price float32 `json:"price" bson:"price"`
+	// Source of the event, e.g. the MLS or public records
+	Source string `json:"source" bson:"source"`
+}
+
 type HomeInfo struct {
 	Id           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Address      Address            `json:"address" bson:"address"`
@@ -45,6 +69,11 @@ type HomeInfo struct {
 	HOADues      float32            `json:"hoa_dues" bson:"hoa_dues"`
 	Parking      string             `json:"parking" bson:"parking"`
 	Schools      []School           `json:"schools" bson:"schools"`
+	// Status of the listing: for-sale, pending or sold
+	Status  string       `json:"status" bson:"status"`
+	History []PriceEvent `json:"history" bson:"history"`
+	// Where the coordinates are from and how well they match the address
+	Geo GeoMatch `json:"geo" bson:"geo"`
 }
 
 type FuelEconomy struct {
@@ -70,7 +99,58 @@ type CarInfo struct {
 	Transmission   string             `json:"transmission" bson:"transmission"`
 	DriveType      string             `json:"drive_type" bson:"drive_type"`
 	MilesPerGallon FuelEconomy        `json:"miles_per_gallon" bson:"miles_per_gallon"`
-	Vin            int64              `json:"vin" bson:"vin"`
+	Vin            string             `json:"vin" bson:"vin"`
+	StockNumber    string             `json:"stock_number" bson:"stock_number"`
 	Features       []string           `json:"features" bson:"features"`
 	Url            string             `json:"url" bson:"url"`
 }
+
+type PriceRange struct {
+	Unit string  `json:"unit" bson:"unit"`
+	Min  float32 `json:"min" bson:"min"`
+	Max  float32 `json:"max" bson:"max"`
+}
+
+type Floorplan struct {
+	Name      string     `json:"name" bson:"name"`
+	Bedrooms  float32    `json:"bedrooms" bson:"bedrooms"`
+	Bathrooms float32    `json:"bathrooms" bson:"bathrooms"`
+	UnitArea  Area       `json:"unit_area" bson:"unit_area"`
+	Rent      PriceRange `json:"rent" bson:"rent"`
+	Available string     `json:"available" bson:"available"`
+}
+
+type PetPolicy struct {
+	Type    string   `json:"type" bson:"type"`
+	Allowed bool     `json:"allowed" bson:"allowed"`
+	Fees    []string `json:"fees" bson:"fees"`
+}
+
+// Apartment info of a rental property
+type ApartmentInfo struct {
+	Id          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Address     Address            `json:"address" bson:"address"`
+	Description string             `json:"description" bson:"description"`
+	Rent        PriceRange         `json:"rent" bson:"rent"`
+	Deposit     float32            `json:"deposit" bson:"deposit"`
+	Floorplans  []Floorplan        `json:"floorplans" bson:"floorplans"`
+	PetPolicies []PetPolicy        `json:"pet_policies" bson:"pet_policies"`
+	Amenities   []string           `json:"amenities" bson:"amenities"`
+	LeaseTerms  []string           `json:"lease_terms" bson:"lease_terms"`
+	Url         string             `json:"url" bson:"url"`
+}
+
+// Job posting of a job board
+type JobPosting struct {
+	Id            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Title         string             `json:"title" bson:"title"`
+	Company       string             `json:"company" bson:"company"`
+	Location      string             `json:"location" bson:"location"`
+	Salary        PriceRange         `json:"salary" bson:"salary"`
+	WorkplaceType string             `json:"workplace_type" bson:"workplace_type"`
+	PostedDate    string             `json:"posted_date" bson:"posted_date"`
+	Description   string             `json:"description" bson:"description"`
+	ApplyUrl      string             `json:"apply_url" bson:"apply_url"`
+	Url           string             `json:"url" bson:"url"`
+}
@@ -1,5 +0,0 @@
-package internal
-
-func UploadHouse() error {
-	return nil
-}
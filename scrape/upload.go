@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+	"github.com/mikehquan19/useful-scraper/scraper/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const UPLOAD_TIMEOUT = 2 * time.Minute
+
+// Uploaders of the parsed objects by object type
+var UPLOADERS = map[string]func(ctx context.Context, db *mongo.Database, inFile string) error{
+	"house": uploadHouses,
+	"car":   uploadCars,
+}
+
+// upload uploads the parsed objects of the object type in the data dir to the MongoDB given by the environment
+func upload(ctx context.Context, dataDir string, objectType string) error {
+	upload, exists := UPLOADERS[objectType]
+	if !exists {
+		return fmt.Errorf("Uploading %ss is currently not supported yet.", objectType)
+	}
+	database := os.Getenv("MONGODB_DATABASE")
+	if database == "" {
+		return fmt.Errorf("MongoDB database name not available.")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, UPLOAD_TIMEOUT)
+	defer cancel()
+
+	client, err := mongodb.Connect(ctx, os.Getenv("MONGODB_URI"))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+	return upload(ctx, client.Database(database), filepath.Join(dataDir, OUTPUT_FILES[objectType]))
+}
+
+func uploadHouses(ctx context.Context, db *mongo.Database, inFile string) error {
+	homeInfos, err := scraper.ReadFromFile[*object.HomeInfo](inFile)
+	if err != nil {
+		return fmt.Errorf("Failed to read parsed houses\n%s", err)
+	}
+	coll := db.Collection(getEnv("MONGODB_HOUSE_COLLECTION", "houses"))
+	_, err = mongodb.UploadHouses(ctx, coll, homeInfos)
+	return err
+}
+
+func uploadCars(ctx context.Context, db *mongo.Database, inFile string) error {
+	carInfos, err := scraper.ReadFromFile[*object.CarInfo](inFile)
+	if err != nil {
+		return fmt.Errorf("Failed to read parsed cars\n%s", err)
+	}
+	coll := db.Collection(getEnv("MONGODB_CAR_COLLECTION", "cars"))
+	_, err = mongodb.UploadCars(ctx, coll, carInfos)
+	return err
+}
+
+// getEnv gets the value from the environment, or the default value when it's empty
+func getEnv(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,20 @@
+package apartments
+
+// Apartments.com paths of the supported cities, which can be changed by Options.CityHrefs
+var DEFAULT_CITY_HREFS = map[string]string{
+	"richardson":   "/richardson-tx/",
+	"dallas":       "/dallas-tx/",
+	"plano":        "/plano-tx/",
+	"frisco":       "/frisco-tx/",
+	"mckinney":     "/mckinney-tx/",
+	"irving":       "/irving-tx/",
+	"allen":        "/allen-tx/",
+	"southlake":    "/southlake-tx/",
+	"coppell":      "/coppell-tx/",
+	"flower-mound": "/flower-mound-tx/",
+	"garland":      "/garland-tx/",
+	"grapevine":    "/grapevine-tx/",
+	"prosper":      "/prosper-tx/",
+	"murphy":       "/murphy-tx/",
+	"keller":       "/keller-tx/",
+}
@@ -0,0 +1,232 @@
+package apartments
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ParseApartments parses the apartment info of the HTML files saved under the directory.
+// A *scraper.PartialError is returned with the apartments when some of the files are skipped.
+func ParseApartments(ctx context.Context, dirName string) ([]*object.ApartmentInfo, error) {
+	var apartmentInfos []*object.ApartmentInfo
+	fmt.Println("Parsing apartment infos...")
+
+	parseErrs, err := scraper.ParseHTMLFiles(ctx, dirName, func(path string, htmlContent *goquery.Document) error {
+		apartmentInfo, err := ParseApartmentHTML(path, htmlContent)
+		if err != nil {
+			return err
+		}
+		apartmentInfos = append(apartmentInfos, apartmentInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Parsed %d apartment infos completely!\n", len(apartmentInfos))
+	return apartmentInfos, scraper.NewPartialError(len(apartmentInfos), parseErrs)
+}
+
+// ParseApartmentHTML parses the apartment info of the apartment's saved HTML,
+// the path is the path of the file or the page's link
+func ParseApartmentHTML(path string, htmlContent *goquery.Document) (*object.ApartmentInfo, error) {
+	url := htmlContent.Find(".apartment-url").AttrOr("data-url", "")
+	parseErr := func(field string, err error) error {
+		return &scraper.ParseError{Url: cmp.Or(url, path), Field: field, Err: err}
+	}
+
+	// Parse the value from the HTML doc
+	name := strings.TrimSpace(htmlContent.Find("#propertyName").Text())
+	if name == "" {
+		// Skip this apartment if it doesn't even have the name
+		return nil, parseErr("name", fmt.Errorf("Name missing, which is non-parsable"))
+	}
+	address, err := getApartmentAddress(htmlContent)
+	if err != nil {
+		return nil, parseErr("address", err)
+	}
+
+	feesMap := getFeesAndPolicies(htmlContent)
+	return &object.ApartmentInfo{
+		Id:          primitive.NewObjectID(),
+		Name:        name,
+		Address:     address,
+		Description: strings.TrimSpace(htmlContent.Find("#descriptionSection p").Text()),
+		Rent:        getRentRange(htmlContent),
+		Deposit:     getDeposit(feesMap),
+		Floorplans:  getFloorplans(htmlContent),
+		PetPolicies: getPetPolicies(feesMap),
+		Amenities:   getAmenities(htmlContent),
+		LeaseTerms:  append(feesMap["Lease Terms"], feesMap["Lease Options"]...),
+		Url:         url,
+	}, nil
+}
+
+func getApartmentAddress(content *goquery.Document) (object.Address, error) {
+	addrContainer := content.Find(".propertyAddressContainer")
+	street := strings.TrimSpace(addrContainer.Find(".delivery-address span").First().Text())
+	stateAndZip := strings.Fields(addrContainer.Find(".stateZipContainer").Text())
+	if street == "" || len(stateAndZip) < 2 {
+		return object.Address{}, fmt.Errorf("Address missing info, which is non-parsable")
+	}
+
+	// The city is the span right before the state and zip code
+	city := strings.Trim(addrContainer.Find(".stateZipContainer").Prev().Text(), " ,\n")
+	return object.Address{
+		Street:  strings.TrimRight(street, ","),
+		City:    city,
+		State:   stateAndZip[0],
+		Zipcode: stateAndZip[1],
+	}, nil
+}
+
+func getRentRange(content *goquery.Document) object.PriceRange {
+	var rentText string
+	content.Find(".priceBedRangeInfo .column").Each(func(i int, s *goquery.Selection) {
+		if strings.Contains(s.Find(".rentInfoLabel").Text(), "Monthly Rent") {
+			rentText = s.Find(".rentInfoDetail").Text()
+		}
+	})
+	return scraper.ParsePriceRange(rentText, "month")
+}
+
+func getFloorplans(content *goquery.Document) []object.Floorplan {
+	var floorplans []object.Floorplan
+	content.Find(".pricingGridItem").Each(func(i int, s *goquery.Selection) {
+		floorplan := object.Floorplan{
+			Name:      strings.TrimSpace(s.Find(".modelName").First().Text()),
+			Rent:      scraper.ParsePriceRange(s.Find(".rentLabel").First().Text(), "month"),
+			Available: strings.TrimSpace(s.Find(".availabilityInfo").First().Text()),
+		}
+
+		// Details are displayed as "2 beds", "1.5 baths", "950 sq ft"
+		s.Find(".detailsTextWrapper span").Each(func(j int, d *goquery.Selection) {
+			text := strings.ToLower(d.Text())
+			value := scraper.StrToFloat32(scraper.NUMBER_REGEX.FindString(strings.ReplaceAll(text, ",", "")))
+			switch {
+			case strings.Contains(text, "studio"):
+				floorplan.Bedrooms = 0
+			case strings.Contains(text, "bed"):
+				floorplan.Bedrooms = value
+			case strings.Contains(text, "bath"):
+				floorplan.Bathrooms = value
+			case strings.Contains(text, "sq ft"):
+				floorplan.UnitArea = object.Area{Unit: "sqft", Value: value}
+			}
+		})
+		floorplans = append(floorplans, floorplan)
+	})
+	return floorplans
+}
+
+// getFeesAndPolicies maps each fees and policies card title to its listed items
+func getFeesAndPolicies(content *goquery.Document) map[string][]string {
+	feesMap := make(map[string][]string)
+	content.Find("#feesSection .feespolicies").Each(func(i int, s *goquery.Selection) {
+		title := strings.TrimSpace(s.Find(".header-column").First().Text())
+		s.Find("li").Each(func(j int, item *goquery.Selection) {
+			var parts []string
+			item.Find(".column, .column-right").Each(func(k int, c *goquery.Selection) {
+				if text := strings.TrimSpace(c.Text()); text != "" {
+					parts = append(parts, text)
+				}
+			})
+			if len(parts) == 0 {
+				parts = append(parts, strings.TrimSpace(item.Text()))
+			}
+			feesMap[title] = append(feesMap[title], strings.Join(parts, ": "))
+		})
+	})
+	return feesMap
+}
+
+// Keywords of the pet policy cards' titles, e.g. "Dogs Allowed", whose deposits are the pets'
+var PET_TITLE_KEYWORDS = []string{"allowed", "cat", "dog", "pet"}
+
+// getDeposit gets the security deposit of the fees cards, the pet deposits are in the pet policies.
+// The bare "Deposit" is only taken from the fees card, when there's no security deposit.
+func getDeposit(feesMap map[string][]string) float32 {
+	// The cards are looked at in order of their titles, so the same deposit is got every time
+	var titles []string
+	for title := range feesMap {
+		if !isPetTitle(title) {
+			titles = append(titles, title)
+		}
+	}
+	sort.Strings(titles)
+
+	depositAmount := func(fee string) float32 {
+		return scraper.StrToFloat32(scraper.NUMBER_REGEX.FindString(strings.ReplaceAll(fee, ",", "")))
+	}
+	for _, title := range titles {
+		for _, fee := range feesMap[title] {
+			if strings.HasPrefix(strings.ToLower(fee), "security deposit") {
+				return depositAmount(fee)
+			}
+		}
+	}
+	for _, title := range titles {
+		if !strings.Contains(strings.ToLower(title), "fee") {
+			continue
+		}
+		for _, fee := range feesMap[title] {
+			if strings.HasPrefix(strings.ToLower(fee), "deposit") {
+				return depositAmount(fee)
+			}
+		}
+	}
+	return 0
+}
+
+// isPetTitle checks if the card's title is of a pet policy
+func isPetTitle(title string) bool {
+	lowerTitle := strings.ToLower(title)
+	for _, keyword := range PET_TITLE_KEYWORDS {
+		if strings.Contains(lowerTitle, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func getPetPolicies(feesMap map[string][]string) []object.PetPolicy {
+	var titles []string
+	for title := range feesMap {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	var petPolicies []object.PetPolicy
+	for _, title := range titles {
+		// Titles are like "Dogs Allowed", "Cats Allowed" or "No Pets Allowed"
+		lowerTitle := strings.ToLower(title)
+		if !strings.Contains(lowerTitle, "allowed") {
+			continue
+		}
+		petType := strings.TrimPrefix(strings.Replace(title, "Allowed", "", 1), "No ")
+		petPolicies = append(petPolicies, object.PetPolicy{
+			Type:    strings.TrimSpace(petType),
+			Allowed: !strings.HasPrefix(lowerTitle, "no "),
+			Fees:    feesMap[title],
+		})
+	}
+	return petPolicies
+}
+
+func getAmenities(content *goquery.Document) []string {
+	var amenities []string
+	content.Find("#amenitiesSection .specInfo").Each(func(i int, s *goquery.Selection) {
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			amenities = append(amenities, text)
+		}
+	})
+	return amenities
+}
@@ -0,0 +1,60 @@
+package apartments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// feesCard gets the HTML of a fees and policies card with the items
+func feesCard(title string, items ...string) string {
+	var builder strings.Builder
+	builder.WriteString(`<div class="feespolicies"><div class="header-column">` + title + `</div><ul>`)
+	for _, item := range items {
+		name, value, _ := strings.Cut(item, ": ")
+		builder.WriteString(`<li><div class="column">` + name + `</div><div class="column-right">` + value + `</div></li>`)
+	}
+	builder.WriteString(`</ul></div>`)
+	return builder.String()
+}
+
+func TestGetDeposit(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []string
+		want  float32
+	}{
+		{
+			"pet deposit before the fees",
+			[]string{feesCard("Dogs Allowed", "Deposit: $300", "Monthly Pet Rent: $25"), feesCard("Fees", "Deposit: $500")},
+			500,
+		},
+		{
+			"security deposit",
+			[]string{
+				feesCard("Cats Allowed", "Deposit: $200"),
+				feesCard("Fees", "Deposit: $1,000"),
+				feesCard("Other Fees", "Security Deposit: $750"),
+			},
+			750,
+		},
+		{"only the pet deposit", []string{feesCard("Pet Policies", "Deposit: $300")}, 0},
+		{"deposit outside the fees", []string{feesCard("Parking", "Deposit: $50")}, 0},
+		{"no cards", nil, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := goquery.NewDocumentFromReader(strings.NewReader(
+				`<div id="feesSection">` + strings.Join(test.cards, "") + `</div>`,
+			))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := getDeposit(getFeesAndPolicies(content)); got != test.want {
+				t.Errorf("getDeposit() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
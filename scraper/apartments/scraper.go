@@ -0,0 +1,159 @@
+/* Apartment rentals scraper from Apartments.com */
+
+package apartments
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/mikehquan19/useful-scraper/scraper"
+)
+
+const APARTMENTS_URL string = "https://www.apartments.com"
+
+func init() {
+	scraper.RegisterSource(New(Options{}))
+}
+
+// Options of the Apartments.com source
+type Options struct {
+	// Apartments.com paths of the cities by name, DEFAULT_CITY_HREFS if nil
+	CityHrefs map[string]string
+}
+
+// Source of the apartments for rent on Apartments.com
+type Source struct {
+	opts Options
+}
+
+// New creates the Apartments.com source with the options
+func New(opts Options) *Source {
+	if opts.CityHrefs == nil {
+		opts.CityHrefs = DEFAULT_CITY_HREFS
+	}
+	return &Source{opts: opts}
+}
+
+func (src *Source) Name() string {
+	return "apartments.com"
+}
+
+func (src *Source) ObjectType() string {
+	return "apartment"
+}
+
+func (src *Source) ListLinks(cdpCtx context.Context, query scraper.Query) ([]string, error) {
+	return src.GetApartmentLinks(cdpCtx, query.City, query.Limit)
+}
+
+// DetailName gets the file name from the link, ".../<property-name>/<id>/"
+func (src *Source) DetailName(link string) string {
+	parts := strings.Split(strings.Trim(link, "/"), "/")
+	if len(parts) < 2 {
+		return parts[len(parts)-1]
+	}
+	return parts[len(parts)-2] + "-" + parts[len(parts)-1]
+}
+
+func (src *Source) FetchDetail(cdpCtx context.Context, link string) ([]byte, error) {
+	return GetApartmentHTML(cdpCtx, link)
+}
+
+func (src *Source) Parse(ctx context.Context, dirName string) (any, error) {
+	return ParseApartments(ctx, dirName)
+}
+
+// GetApartmentLinks gets the list of links to each apartment property of the city
+func (src *Source) GetApartmentLinks(cdpCtx context.Context, city string, limit int) ([]string, error) {
+	cityHref, exists := src.opts.CityHrefs[strings.ToLower(city)]
+	if !exists {
+		return nil, fmt.Errorf("The city either doesn't exist or is not supported")
+	}
+
+	var apartmentLinks []string
+	fmt.Println("Scraping apartment links...")
+
+	// Search pages are numbered as /<city>/2/, /<city>/3/, ...
+	for page := 1; limit == 0 || len(apartmentLinks) < limit; page++ {
+		pageUrl := APARTMENTS_URL + cityHref
+		if page > 1 {
+			pageUrl += fmt.Sprintf("%d/", page)
+		}
+
+		var placardNodes []*cdp.Node
+		_, err := scraper.Navigate(cdpCtx, pageUrl,
+			chromedp.WaitVisible("#placardContainer"),
+			chromedp.Nodes("article.placard", &placardNodes, chromedp.ByQueryAll, chromedp.AtLeast(0)),
+		)
+		if err != nil && page == 1 {
+			return nil, err
+		} else if err != nil {
+			// The next pages are unknown without this page, so keep the links scraped so far
+			fmt.Printf("Failed to scrape apartment links of %s\n%s\n", pageUrl, err)
+			scraper.RecordFailure(cdpCtx, pageUrl, err)
+			break
+		}
+		if len(placardNodes) == 0 {
+			// Went past the last page
+			break
+		}
+
+		for _, placardNode := range placardNodes {
+			apartmentUrl, exists := placardNode.Attribute("data-url")
+			if !exists {
+				continue
+			}
+			apartmentLinks = append(apartmentLinks, apartmentUrl)
+		}
+
+		hasNext, err := scraper.ElementExists(cdpCtx, ".paging .next")
+		if err != nil {
+			return nil, err
+		}
+		if !hasNext {
+			break
+		}
+	}
+
+	fmt.Printf("Sucessfully scaped %d apartment links!\n", len(apartmentLinks))
+	return apartmentLinks, nil
+}
+
+// GetApartmentHTML navigates to the apartment's page and gets the HTML of its relevant sections
+func GetApartmentHTML(cdpCtx context.Context, apartmentLink string) ([]byte, error) {
+	var header, rentInfo, pricing, fees, amenities, description string
+
+	_, err := scraper.Navigate(cdpCtx, apartmentLink,
+		chromedp.WaitVisible(".propertyNameRow"),
+		chromedp.OuterHTML("#propertyHeader", &header, chromedp.ByQuery),
+
+		chromedp.WaitVisible(".priceBedRangeInfo"),
+		chromedp.OuterHTML(".priceBedRangeInfo", &rentInfo, chromedp.ByQuery),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, section := range []struct {
+		sel  string
+		name string
+		html *string
+	}{
+		{"#pricingView", "floorplans", &pricing},
+		{"#feesSection", "fees and policies", &fees},
+		{"#amenitiesSection", "amenities", &amenities},
+		{"#descriptionSection", "description", &description},
+	} {
+		if err = scraper.ExtractOrSkip(cdpCtx, section.sel, section.name, section.html); err != nil {
+			return nil, err
+		}
+	}
+
+	return fmt.Appendf(nil,
+		`<div><div class="apartment-url" data-url="%s"></div>%s%s%s%s%s%s</div>`,
+		apartmentLink, header, rentInfo, pricing, fees, amenities, description,
+	), nil
+}
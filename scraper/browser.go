@@ -0,0 +1,157 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Time to wait for an optional section of the page
+const EXTRACT_TIMEOUT = 10 * time.Second
+
+// newBrowser starts the browser and opens its first tab with a consistent fingerprint to
+// scrape sites using cloudfont or other anti-bot algorithms. The browser is killed when
+// the context is done, the cancel function closes it and waits for its process to exit.
+func newBrowser(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	// By default, the context are headful
+	allocOptions := append(
+		chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("disable-blink-features", "AutomationControlled"),
+	)
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, allocOptions...)
+	chromedpCtx, browserCancel := chromedp.NewContext(allocCtx)
+	cancel := func() {
+		// Closing the browser waits for it to exit, the allocator then removes its profile directory
+		browserCancel()
+		allocCancel()
+	}
+
+	// Start the browser, so the first tab can have its own browser context for the proxy
+	if err := chromedp.Run(chromedpCtx); err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("Failed to start the browser\n%w", err)
+	}
+	tabCtx, tabCancel, err := newTab(chromedpCtx)
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("Failed to open tab\n%w", err)
+	}
+
+	return tabCtx, func() {
+		tabCancel()
+		cancel()
+	}, nil
+}
+
+// newTab opens a new tab in the browser of the chromedp context, with its own fingerprint.
+// With proxies, the tab is opened in a new browser context connecting through the next proxy.
+func newTab(cdpCtx context.Context) (context.Context, context.CancelFunc, error) {
+	proxy := getSession(cdpCtx).proxies.assign()
+	tabCtx, cancel := chromedp.NewContext(cdpCtx, proxyContextOptions(proxy)...)
+	tabCtx, err := withIdentity(tabCtx, proxy)
+	if err == nil {
+		err = authenticateProxy(tabCtx, proxy)
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return tabCtx, cancel, nil
+}
+
+// Navigate waits for the turn of the link's domain and navigates to it, then runs the actions.
+// The navigation and the actions are retried together by the retry policy of the run, and the
+// turn is waited for again before each retry.
+func Navigate(cdpCtx context.Context, link string, actions ...chromedp.Action) (*network.Response, error) {
+	var response *network.Response
+	// The turn of the domain is waited for before the attempt's timeout starts, since the backoff
+	// after a block can be longer than the timeout
+	waitTurn := func(ctx context.Context) error {
+		return WaitTurn(ctx, link)
+	}
+	err := withRetry(cdpCtx, "Navigating to "+link, waitTurn, func(ctx context.Context) (err error) {
+		// The outcome of each attempt counts towards the health of the tab's proxy
+		defer func() { reportProxy(cdpCtx, err) }()
+
+		if err := countNavigation(cdpCtx); err != nil {
+			return err
+		}
+
+		response, err = chromedp.RunResponse(ctx, chromedp.Navigate(link))
+		if err != nil {
+			return err
+		}
+
+		status, err := classifyPage(ctx, response)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case PAGE_NOT_FOUND:
+			return fmt.Errorf("%s\n%w", link, ErrNotFound)
+		case PAGE_BLOCKED:
+			backoff := getSession(cdpCtx).rateLimiter.Penalize(link)
+			fmt.Printf("Blocked when navigating to %s, backing off for %s\n", link, backoff)
+			if err = resetIdentity(cdpCtx); err != nil {
+				return err
+			}
+			return fmt.Errorf("%s\n%w", link, ErrBlocked)
+		}
+		getSession(cdpCtx).rateLimiter.Forgive(link)
+
+		if response != nil && response.Status >= 400 {
+			return &StatusError{Url: link, Status: response.Status}
+		}
+		err = chromedp.Run(ctx, actions...)
+		if errors.Is(err, context.DeadlineExceeded) {
+			// The page is loaded, but the elements waited for never showed up
+			return &SelectorError{Url: link, Err: err}
+		}
+		return err
+	})
+	return response, err
+}
+
+// WaitTurn waits until the link's domain can be loaded from again, for the loads
+// which aren't navigations, e.g. clicking "see more"
+func WaitTurn(ctx context.Context, link string) error {
+	return getSession(ctx).rateLimiter.Wait(ctx, link)
+}
+
+// ElementExists checks if there's an element of the selector on the page
+func ElementExists(ctx context.Context, sel string) (bool, error) {
+	var nodes []*cdp.Node
+	err := chromedp.Run(ctx, chromedp.Nodes(sel, &nodes, chromedp.AtLeast(0)))
+	if err != nil {
+		return false, fmt.Errorf("Failed to look for %s\n%w", sel, err)
+	}
+	return len(nodes) > 0, nil
+}
+
+// ExtractOrSkip try to extract html from selector or skip if it's hanging,
+// the name of the section is printed when it's skipped
+func ExtractOrSkip(cdpCtx context.Context, sel string, name string, html *string) error {
+	// Use the timeout context
+	timeoutCtx, timeoutCancel := context.WithTimeout(cdpCtx, EXTRACT_TIMEOUT)
+	defer timeoutCancel()
+
+	err := chromedp.Run(timeoutCtx,
+		chromedp.WaitVisible(sel),
+		chromedp.OuterHTML(sel, html, chromedp.ByQuery),
+	)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			fmt.Printf("%s not available in the website\n", name)
+		} else {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Interval the captured responses are checked at while waiting for them
+const CAPTURE_POLL_INTERVAL = 200 * time.Millisecond
+
+// ResponseCapture captures the bodies of the tab's responses matching the URLs,
+// e.g. the calls of a site's internal API, through CDP's network events
+type ResponseCapture struct {
+	match func(url string) bool
+
+	mu sync.Mutex
+	// URLs of the matched responses by their request
+	urls map[network.RequestID]string
+	// Matched responses which are finished loading, so their bodies can be read
+	finished []network.RequestID
+}
+
+// CaptureResponses starts capturing the tab's responses whose URLs match, the capture
+// is stopped by the cancel function
+func CaptureResponses(cdpCtx context.Context, match func(url string) bool) (*ResponseCapture, context.CancelFunc) {
+	capture := &ResponseCapture{match: match, urls: make(map[network.RequestID]string)}
+	// The listener is removed when its context is done
+	listenCtx, cancel := context.WithCancel(cdpCtx)
+	chromedp.ListenTarget(listenCtx, func(ev any) {
+		capture.mu.Lock()
+		defer capture.mu.Unlock()
+		switch ev := ev.(type) {
+		case *network.EventResponseReceived:
+			if ev.Response != nil && capture.match(ev.Response.URL) {
+				capture.urls[ev.RequestID] = ev.Response.URL
+			}
+		case *network.EventLoadingFinished:
+			if _, exists := capture.urls[ev.RequestID]; exists {
+				capture.finished = append(capture.finished, ev.RequestID)
+			}
+		}
+	})
+	return capture, cancel
+}
+
+// WaitFor waits until a finished response's URL matches, or the timeout is up
+func (capture *ResponseCapture) WaitFor(ctx context.Context, match func(url string) bool, timeout time.Duration) bool {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(CAPTURE_POLL_INTERVAL)
+	defer ticker.Stop()
+	for {
+		capture.mu.Lock()
+		for _, requestId := range capture.finished {
+			if match(capture.urls[requestId]) {
+				capture.mu.Unlock()
+				return true
+			}
+		}
+		capture.mu.Unlock()
+
+		select {
+		case <-ticker.C:
+		case <-timeoutCtx.Done():
+			return false
+		}
+	}
+}
+
+// Bodies gets the bodies of the finished responses by their URLs. The responses whose
+// bodies are already evicted by the browser are skipped.
+func (capture *ResponseCapture) Bodies(cdpCtx context.Context) (map[string][]byte, error) {
+	capture.mu.Lock()
+	finished := append([]network.RequestID(nil), capture.finished...)
+	urls := make(map[network.RequestID]string, len(finished))
+	for _, requestId := range finished {
+		urls[requestId] = capture.urls[requestId]
+	}
+	capture.mu.Unlock()
+
+	bodies := make(map[string][]byte)
+	for _, requestId := range finished {
+		var body []byte
+		err := chromedp.Run(cdpCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			body, err = network.GetResponseBody(requestId).Do(ctx)
+			return err
+		}))
+		if err != nil && cdpCtx.Err() != nil {
+			return nil, cdpCtx.Err()
+		} else if err != nil {
+			fmt.Printf("Skipped the response of %s\n%s\n", urls[requestId], err)
+			continue
+		}
+		// The later responses of the same URL, e.g. of a retry, replace the earlier ones
+		bodies[urls[requestId]] = body
+	}
+	return bodies, nil
+}
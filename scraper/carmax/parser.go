@@ -0,0 +1,156 @@
+package carmax
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var DIGITS_REGEX = regexp.MustCompile(`\d+`)
+var DISPLACEMENT_REGEX = regexp.MustCompile(`(?i)([\d.]+)\s*L\b`)
+var CYLINDERS_REGEX = regexp.MustCompile(`(?i)\b(?:V|I|H|W|Inline-?|Flat-?)(\d{1,2})\b|(\d{1,2})[- ]?Cyl`)
+var CITY_MPG_REGEX = regexp.MustCompile(`(?i)(\d+)\s*city`)
+var HIGHWAY_MPG_REGEX = regexp.MustCompile(`(?i)(\d+)\s*(?:hwy|highway)`)
+
+// Fuel types as displayed in the engine or fuel type specs
+var FUEL_TYPES = []string{"plug-in hybrid", "hybrid", "electric", "diesel", "flex fuel", "gas"}
+
+// ParseCars parses the car info of the HTML files saved under the directory.
+// A *scraper.PartialError is returned with the cars when some of the files are skipped.
+func ParseCars(ctx context.Context, dirName string) ([]*object.CarInfo, error) {
+	var carInfos []*object.CarInfo
+	fmt.Println("Parsing car infos...")
+
+	parseErrs, err := scraper.ParseHTMLFiles(ctx, dirName, func(filePath string, htmlContent *goquery.Document) error {
+		carInfo, err := ParseCarHTML(filePath, htmlContent)
+		if err != nil {
+			return err
+		}
+		carInfos = append(carInfos, carInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Parsed %d car infos completely!\n", len(carInfos))
+	return carInfos, scraper.NewPartialError(len(carInfos), parseErrs)
+}
+
+// ParseCarHTML parses the car info of the car's saved HTML, the filePath is the path of the file
+// or the page's link. The stock number is taken from the end of the page's link.
+func ParseCarHTML(filePath string, content *goquery.Document) (*object.CarInfo, error) {
+	url := content.Find(".car-url").AttrOr("data-url", filePath)
+
+	// Parse the make, model, and year from the title like "2020 Honda Civic EX"
+	titleParts := strings.Fields(content.Find("#car-header-car-basic-info").Text())
+	if len(titleParts) < 3 {
+		return nil, &scraper.ParseError{Url: url, Field: "title", Err: fmt.Errorf("Title missing info, which is non-parsable")}
+	}
+	year := scraper.StrToInt32(titleParts[0])
+	if year == 0 {
+		return nil, &scraper.ParseError{Url: url, Field: "year", Err: fmt.Errorf("Invalid year %s", titleParts[0])}
+	}
+
+	// Parse the milage and price
+	milage := DIGITS_REGEX.FindString(strings.ReplaceAll(content.Find(".car-header-milage").Text(), ",", ""))
+	price := DIGITS_REGEX.FindString(strings.ReplaceAll(content.Find(".car-price").Text(), ",", ""))
+	if price == "" {
+		return nil, &scraper.ParseError{Url: url, Field: "price", Err: fmt.Errorf("Price missing, which is non-parsable")}
+	}
+
+	specsMap := getCarSpecs(content)
+	return &object.CarInfo{
+		Id:             primitive.NewObjectID(),
+		Make:           titleParts[1],
+		Model:          strings.Join(titleParts[2:], " "),
+		Year:           year,
+		Mileage:        scraper.StrToFloat32(milage),
+		Price:          scraper.StrToFloat32(price),
+		Color:          getSpec(specsMap, "exterior color", "color"),
+		Engine:         getEngine(getSpec(specsMap, "engine"), getSpec(specsMap, "fuel type")),
+		Transmission:   getSpec(specsMap, "transmission"),
+		DriveType:      getSpec(specsMap, "drive type", "drivetrain", "drive train"),
+		MilesPerGallon: getFuelEconomy(getSpec(specsMap, "mpg", "fuel economy")),
+		Vin:            strings.ToUpper(getSpec(specsMap, "vin")),
+		Features:       getCarFeatures(content),
+		StockNumber:    strings.TrimSuffix(path.Base(strings.TrimRight(url, "/")), ".html"),
+		Url:            content.Find(".car-url").AttrOr("data-url", ""),
+	}, nil
+}
+
+// getCarSpecs maps each spec label to its value, e.g. "Transmission" to "Automatic"
+func getCarSpecs(content *goquery.Document) map[string]string {
+	specsMap := make(map[string]string)
+	content.Find(".spec-item").Each(func(i int, s *goquery.Selection) {
+		label := strings.TrimSpace(strings.TrimSuffix(s.Find(".spec-label").Text(), ":"))
+		value := strings.TrimSpace(s.Find(".spec-value").Text())
+		if label != "" && value != "" {
+			specsMap[strings.ToLower(label)] = value
+		}
+	})
+	return specsMap
+}
+
+// getSpec gets the value of the first label listed in the specs, as CarMax names some specs differently
+func getSpec(specsMap map[string]string, labels ...string) string {
+	for _, label := range labels {
+		if value, ok := specsMap[label]; ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// getEngine parses the engine like "2.5L Inline-4 Gas" or "3.5L V6"
+func getEngine(engineText string, fuelText string) object.Engine {
+	engine := object.Engine{}
+	if match := DISPLACEMENT_REGEX.FindStringSubmatch(engineText); match != nil {
+		engine.Displacement = scraper.StrToFloat32(match[1])
+	}
+	if match := CYLINDERS_REGEX.FindStringSubmatch(engineText); match != nil {
+		engine.Cylinders = int(scraper.StrToInt32(match[1] + match[2]))
+	}
+
+	// The fuel type is either in its own spec or at the end of the engine spec
+	if fuelText == "" {
+		fuelText = engineText
+	}
+	lowerFuel := strings.ToLower(fuelText)
+	for _, fuelType := range FUEL_TYPES {
+		if strings.Contains(lowerFuel, fuelType) {
+			engine.FuelType = fuelType
+			break
+		}
+	}
+	return engine
+}
+
+// getFuelEconomy parses the fuel economy like "27 City / 35 Hwy"
+func getFuelEconomy(mpgText string) object.FuelEconomy {
+	fuelEconomy := object.FuelEconomy{}
+	if match := CITY_MPG_REGEX.FindStringSubmatch(mpgText); match != nil {
+		fuelEconomy.CityMPG = scraper.StrToFloat32(match[1])
+	}
+	if match := HIGHWAY_MPG_REGEX.FindStringSubmatch(mpgText); match != nil {
+		fuelEconomy.HighwayMPG = scraper.StrToFloat32(match[1])
+	}
+	return fuelEconomy
+}
+
+func getCarFeatures(content *goquery.Document) []string {
+	var features []string
+	content.Find("#car-page-features li").Each(func(i int, s *goquery.Selection) {
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			features = append(features, text)
+		}
+	})
+	return features
+}
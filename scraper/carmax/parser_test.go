@@ -0,0 +1,59 @@
+package carmax
+
+import (
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+func TestDetailName(t *testing.T) {
+	src := New()
+	for _, link := range []string{"https://www.carmax.com/car/26102431", "https://www.carmax.com/car/26102431/"} {
+		if got := src.DetailName(link); got != "26102431" {
+			t.Errorf("DetailName(%q) = %q, want 26102431", link, got)
+		}
+	}
+}
+
+func TestGetEngine(t *testing.T) {
+	tests := []struct {
+		engineText string
+		fuelText   string
+		want       object.Engine
+	}{
+		{"2.5L Inline-4 Gas", "", object.Engine{Cylinders: 4, FuelType: "gas", Displacement: 2.5}},
+		{"3.5L V6", "Gas", object.Engine{Cylinders: 6, FuelType: "gas", Displacement: 3.5}},
+		{"2.0L I4 Plug-In Hybrid", "", object.Engine{Cylinders: 4, FuelType: "plug-in hybrid", Displacement: 2}},
+		{"1.8L 4-Cyl", "Hybrid", object.Engine{Cylinders: 4, FuelType: "hybrid", Displacement: 1.8}},
+		{"2.4L Flat-4", "", object.Engine{Cylinders: 4, Displacement: 2.4}},
+		{"Electric Motor", "", object.Engine{FuelType: "electric"}},
+		{"", "", object.Engine{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.engineText, func(t *testing.T) {
+			if got := getEngine(test.engineText, test.fuelText); got != test.want {
+				t.Errorf("getEngine(%q, %q) = %+v, want %+v", test.engineText, test.fuelText, got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetFuelEconomy(t *testing.T) {
+	tests := []struct {
+		mpgText string
+		want    object.FuelEconomy
+	}{
+		{"27 City / 35 Hwy", object.FuelEconomy{CityMPG: 27, HighwayMPG: 35}},
+		{"22 city, 30 highway", object.FuelEconomy{CityMPG: 22, HighwayMPG: 30}},
+		{"N/A", object.FuelEconomy{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.mpgText, func(t *testing.T) {
+			if got := getFuelEconomy(test.mpgText); got != test.want {
+				t.Errorf("getFuelEconomy(%q) = %+v, want %+v", test.mpgText, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package carmax
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/mikehquan19/useful-scraper/scraper"
+)
+
+const carmaxBaseUrl = "https://www.carmax.com"
+const SEE_MORE_RENDER_DELAY = 1 * time.Second
+
+func init() {
+	scraper.RegisterSource(New())
+}
+
+// Source of the used cars for sale on CarMax
+type Source struct{}
+
+// New creates the CarMax source
+func New() *Source {
+	return &Source{}
+}
+
+func (src *Source) Name() string {
+	return "carmax"
+}
+
+func (src *Source) ObjectType() string {
+	return "car"
+}
+
+func (src *Source) ListLinks(cdpCtx context.Context, query scraper.Query) ([]string, error) {
+	return ScrapeCarLinks(cdpCtx, query.City)
+}
+
+// DetailName gets the stock number of the car
+func (src *Source) DetailName(link string) string {
+	return path.Base(strings.TrimRight(link, "/"))
+}
+
+func (src *Source) FetchDetail(cdpCtx context.Context, link string) ([]byte, error) {
+	return ScrapeCarHTML(cdpCtx, link)
+}
+
+func (src *Source) Parse(ctx context.Context, dirName string) (any, error) {
+	return ParseCars(ctx, dirName)
+}
+
+// ScrapeCarLinks gets all the car links of the city
+func ScrapeCarLinks(cdpCtx context.Context, cityId string) ([]string, error) {
+	var carLinks []string
+	_, err := scraper.Navigate(cdpCtx, fmt.Sprintf("%s/cars/%s", carmaxBaseUrl, cityId))
+	if err != nil {
+		return nil, err
+	}
+
+	// Scroll all the way to the end of the website
+	for {
+		hasMore, err := scraper.ElementExists(cdpCtx, "#see-more-button")
+		if err != nil {
+			return nil, err
+		}
+		if !hasMore {
+			break
+		}
+		// Each click loads more cars from CarMax, so it's paced like a navigation
+		if err = scraper.WaitTurn(cdpCtx, carmaxBaseUrl); err != nil {
+			return nil, err
+		}
+		err = chromedp.Run(cdpCtx,
+			chromedp.ScrollIntoView("#see-more-button", chromedp.ByQuery),
+			chromedp.Click("#see-more-button", chromedp.ByQuery),
+			// Give the loaded cars time to render
+			chromedp.Sleep(SEE_MORE_RENDER_DELAY),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var carLinkNodes []*cdp.Node
+	err = chromedp.Run(cdpCtx,
+		chromedp.Nodes(".scct--make-model-info-link", &carLinkNodes, chromedp.ByQueryAll),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for i, carLinkNode := range carLinkNodes {
+		carHref, hrefExists := carLinkNode.Attribute("href")
+		if !hrefExists {
+			return nil, fmt.Errorf("can't find link to car %d", i+1)
+		}
+		carLinks = append(carLinks, fmt.Sprintf("%s%s", carmaxBaseUrl, carHref))
+	}
+
+	return carLinks, nil
+}
+
+// ScrapeCarHTML navigates to the car's page and gets the HTML of its relevant sections
+func ScrapeCarHTML(cdpCtx context.Context, carLink string) ([]byte, error) {
+	var title, price, milage, specs, features string
+
+	_, err := scraper.Navigate(cdpCtx, carLink,
+		chromedp.WaitVisible("#car-header-car-basic-info"),
+		chromedp.OuterHTML("#car-header-car-basic-info", &title, chromedp.ByQuery),
+		chromedp.OuterHTML(".car-header-milage", &milage, chromedp.ByQuery),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	priceSel := "#default-price-display"
+	hasPrice, err := scraper.ElementExists(cdpCtx, priceSel)
+	if err != nil {
+		return nil, err
+	}
+	if !hasPrice {
+		// Car whose page shows the drop of price
+		priceSel = "#price-drop-header-display .css-pff6mx"
+		if hasPrice, err = scraper.ElementExists(cdpCtx, priceSel); err != nil {
+			return nil, err
+		}
+	}
+	if !hasPrice {
+		return nil, &scraper.SelectorError{Url: carLink, Selector: priceSel, Err: fmt.Errorf("Price not available in the website")}
+	}
+	timeoutCtx, timeoutCancel := context.WithTimeout(cdpCtx, scraper.EXTRACT_TIMEOUT)
+	defer timeoutCancel()
+	err = chromedp.Run(timeoutCtx, chromedp.OuterHTML(priceSel, &price, chromedp.ByQuery))
+	if err != nil {
+		return nil, &scraper.SelectorError{Url: carLink, Selector: priceSel, Err: err}
+	}
+
+	// Some cars don't list all of the specs and features
+	if err = scraper.ExtractOrSkip(cdpCtx, "#car-page-specs", "specs", &specs); err != nil {
+		return nil, err
+	}
+	if err = scraper.ExtractOrSkip(cdpCtx, "#car-page-features", "features", &features); err != nil {
+		return nil, err
+	}
+
+	// The price is wrapped so that it's found the same way for both price displays
+	return fmt.Appendf(nil,
+		`<div><div class="car-url" data-url="%s"></div>%s<div class="car-price">%s</div>%s%s%s</div>`,
+		carLink, title, price, milage, specs, features,
+	), nil
+}
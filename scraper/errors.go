@@ -0,0 +1,74 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrBlocked = errors.New("blocked by the site's bot protection")
+var ErrNotFound = errors.New("page not found")
+var ErrSelectorMissing = errors.New("selector missing from the page")
+var ErrParse = errors.New("failed to parse")
+
+// SelectorError is returned when the element of the selector doesn't show up on the page.
+// It matches ErrSelectorMissing and the error of the wait, e.g. context.DeadlineExceeded.
+type SelectorError struct {
+	Url string
+	// Selector of the missing element, empty when it's one of the navigation's waits
+	Selector string
+	Err      error
+}
+
+func (err *SelectorError) Error() string {
+	if err.Selector == "" {
+		return fmt.Sprintf("%s: %s\n%s", err.Url, ErrSelectorMissing, err.Err)
+	}
+	return fmt.Sprintf("%s: %s %s\n%s", err.Url, ErrSelectorMissing, err.Selector, err.Err)
+}
+
+func (err *SelectorError) Unwrap() []error {
+	return []error{ErrSelectorMissing, err.Err}
+}
+
+// ParseError is returned when a field of the saved page can't be parsed, so the page is skipped
+type ParseError struct {
+	// URL of the page, or the path of its saved HTML
+	Url   string
+	Field string
+	Err   error
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s %s\n%s", err.Url, ErrParse, err.Field, err.Err)
+}
+
+func (err *ParseError) Unwrap() []error {
+	return []error{ErrParse, err.Err}
+}
+
+// PartialError is returned when the run is finished, but some of the pages failed or were skipped.
+// It matches the errors of those pages, e.g. ErrBlocked.
+type PartialError struct {
+	Succeeded int
+	Errs      []error
+}
+
+func (err *PartialError) Error() string {
+	return fmt.Sprintf("%d succeeded, %d failed", err.Succeeded, len(err.Errs))
+}
+
+func (err *PartialError) Unwrap() []error {
+	return err.Errs
+}
+
+// NewPartialError gets the PartialError of the run, which is nil if nothing failed.
+// The run is failed instead when nothing succeeded, so the errors are joined.
+func NewPartialError(succeeded int, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if succeeded == 0 {
+		return errors.Join(errs...)
+	}
+	return &PartialError{Succeeded: succeeded, Errs: errs}
+}
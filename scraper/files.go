@@ -0,0 +1,164 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+var NUMBER_REGEX = regexp.MustCompile(`[\d.]+`)
+
+// WriteToFile writes the list of objects to file as JSON
+func WriteToFile[T any](objectList []T, outDir string) error {
+	// Convert the list to the json
+	jsonData, err := json.Marshal(objectList)
+	if err != nil {
+		return err
+	}
+
+	// Write to JSON file,
+	err = WriteFileAtomic(outDir, jsonData)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// WriteFileAtomic writes the data to a temporary file next to the file and renames it,
+// so the file is never left half-written when the run is interrupted
+func WriteFileAtomic(fileName string, data []byte) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Nothing is left behind if the file isn't renamed
+	defer os.Remove(tempFile.Name())
+
+	if _, err = tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err = tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err = tempFile.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tempFile.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), fileName)
+}
+
+// ReadFromFile reads the list of objects from the JSON file
+func ReadFromFile[T any](inDir string) ([]T, error) {
+	jsonData, err := os.ReadFile(inDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var objectList []T
+	if err = json.Unmarshal(jsonData, &objectList); err != nil {
+		return nil, err
+	}
+	return objectList, nil
+}
+
+// StrToFloat32 converts the string like "1,234.5" to float32
+func StrToFloat32(str string) float32 {
+	var convertedValue float32
+	cleanedStr := strings.ReplaceAll(str, ",", "")
+	err := json.Unmarshal([]byte(cleanedStr), &convertedValue)
+	if err != nil {
+		// Any problem with parsing string will just result in 0.
+		// Works for "-"
+		return 0
+	}
+	return float32(convertedValue)
+}
+
+// StrToInt32 converts the string like "1,234" to int32
+func StrToInt32(str string) int32 {
+	cleanedStr := strings.ReplaceAll(str, ",", "")
+	convertedValue, err := strconv.ParseInt(cleanedStr, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(convertedValue)
+}
+
+// ParseHTMLFiles parses every saved HTML file under the directory with the parse function.
+// The files failing with ErrParse are skipped and their errors are returned, any other
+// error stops the parsing.
+func ParseHTMLFiles(ctx context.Context, dirName string, parse func(path string, doc *goquery.Document) error) ([]error, error) {
+	var parseErrs []error
+	err := filepath.WalkDir(dirName, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(path, ".tmp") || strings.HasSuffix(path, ".json") {
+			// The file of an interrupted write, or the records of the pages, e.g. settings.json
+			return nil
+		}
+		if !strings.HasSuffix(path, ".html") {
+			return fmt.Errorf("%s must have only HTML files", dirName)
+		}
+
+		// Get the HTML doc from the file
+		htmlFile, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer htmlFile.Close()
+		htmlContent, err := goquery.NewDocumentFromReader(htmlFile)
+		if err != nil {
+			return err
+		}
+
+		err = parse(path, htmlContent)
+		if errors.Is(err, ErrParse) {
+			// Skip this file if it contains some invalid information
+			fmt.Printf("Skipped %s\n", err)
+			parseErrs = append(parseErrs, err)
+			return nil
+		}
+		return err
+	})
+	return parseErrs, err
+}
+
+// ParsePriceRange parses the text like "$1,200 - $2,350" or "$1,200" to the range
+func ParsePriceRange(text string, unit string) object.PriceRange {
+	prices := NUMBER_REGEX.FindAllString(strings.ReplaceAll(text, ",", ""), -1)
+	if len(prices) == 0 {
+		// Listings can show "Call for Rent"
+		return object.PriceRange{Unit: unit}
+	}
+
+	priceRange := object.PriceRange{
+		Unit: unit,
+		Min:  StrToFloat32(prices[0]),
+		Max:  StrToFloat32(prices[0]),
+	}
+	if len(prices) > 1 {
+		priceRange.Max = StrToFloat32(prices[1])
+	}
+	return priceRange
+}
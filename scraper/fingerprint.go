@@ -0,0 +1,249 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Brand of the browser in the client hints, e.g. "Google Chrome" 124
+type Brand struct {
+	Brand   string `json:"brand"`
+	Version string `json:"version"`
+}
+
+type Viewport struct {
+	Width             int64   `json:"width"`
+	Height            int64   `json:"height"`
+	DeviceScaleFactor float64 `json:"device_scale_factor"`
+}
+
+// Fingerprint is a consistent profile of the browser. The user agent, the client hints,
+// navigator.platform, the viewport, the locale and the timezone must all describe the same device.
+type Fingerprint struct {
+	Name      string `json:"name"`
+	UserAgent string `json:"user_agent"`
+	// navigator.platform, e.g. "MacIntel"
+	Platform string `json:"platform"`
+	// Client hints sent in the Sec-CH-UA-* headers and returned by navigator.userAgentData
+	Brands          []Brand `json:"brands"`
+	FullVersion     string  `json:"full_version"`
+	ClientPlatform  string  `json:"client_platform"`
+	PlatformVersion string  `json:"platform_version"`
+	Architecture    string  `json:"architecture"`
+	Mobile          bool    `json:"mobile"`
+
+	Viewport       Viewport `json:"viewport"`
+	Locale         string   `json:"locale"`
+	AcceptLanguage string   `json:"accept_language"`
+	Timezone       string   `json:"timezone"`
+}
+
+var DEFAULT_FINGERPRINTS = []Fingerprint{
+	{
+		Name:            "chrome-macos",
+		UserAgent:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Platform:        "MacIntel",
+		Brands:          []Brand{{"Chromium", "124"}, {"Google Chrome", "124"}, {"Not-A.Brand", "99"}},
+		FullVersion:     "124.0.6367.91",
+		ClientPlatform:  "macOS",
+		PlatformVersion: "14.4.1",
+		Architecture:    "arm",
+		Viewport:        Viewport{Width: 1440, Height: 900, DeviceScaleFactor: 2},
+		Locale:          "en-US",
+		AcceptLanguage:  "en-US,en;q=0.9",
+		Timezone:        "America/Chicago",
+	},
+	{
+		Name:            "chrome-windows",
+		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Platform:        "Win32",
+		Brands:          []Brand{{"Chromium", "124"}, {"Google Chrome", "124"}, {"Not-A.Brand", "99"}},
+		FullVersion:     "124.0.6367.92",
+		ClientPlatform:  "Windows",
+		PlatformVersion: "15.0.0",
+		Architecture:    "x86",
+		Viewport:        Viewport{Width: 1920, Height: 1080, DeviceScaleFactor: 1},
+		Locale:          "en-US",
+		AcceptLanguage:  "en-US,en;q=0.9",
+		Timezone:        "America/New_York",
+	},
+	{
+		Name:            "edge-windows",
+		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0",
+		Platform:        "Win32",
+		Brands:          []Brand{{"Chromium", "124"}, {"Microsoft Edge", "124"}, {"Not-A.Brand", "99"}},
+		FullVersion:     "124.0.2478.67",
+		ClientPlatform:  "Windows",
+		PlatformVersion: "10.0.0",
+		Architecture:    "x86",
+		Viewport:        Viewport{Width: 1536, Height: 864, DeviceScaleFactor: 1.25},
+		Locale:          "en-US",
+		AcceptLanguage:  "en-US,en;q=0.9",
+		Timezone:        "America/Denver",
+	},
+	{
+		Name:            "chrome-linux",
+		UserAgent:       "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Platform:        "Linux x86_64",
+		Brands:          []Brand{{"Chromium", "124"}, {"Google Chrome", "124"}, {"Not-A.Brand", "99"}},
+		FullVersion:     "124.0.6367.91",
+		ClientPlatform:  "Linux",
+		PlatformVersion: "6.5.0",
+		Architecture:    "x86",
+		Viewport:        Viewport{Width: 1920, Height: 1080, DeviceScaleFactor: 1},
+		Locale:          "en-US",
+		AcceptLanguage:  "en-US,en;q=0.9",
+		Timezone:        "America/Los_Angeles",
+	},
+}
+
+// fingerprintRotation hands out the fingerprints to the tabs in turn
+type fingerprintRotation struct {
+	mu       sync.Mutex
+	profiles []Fingerprint
+	next     int
+	// Number of navigations before a tab switches to the next fingerprint, 0 keeps
+	// the tab's fingerprint for the whole session
+	rotateEvery int
+}
+
+func newFingerprintRotation(profiles []Fingerprint, rotateEvery int) *fingerprintRotation {
+	if len(profiles) == 0 {
+		profiles = DEFAULT_FINGERPRINTS
+	}
+	// Start from a random profile, so each run doesn't look the same
+	return &fingerprintRotation{profiles: profiles, next: rand.N(len(profiles)), rotateEvery: rotateEvery}
+}
+
+func (rotation *fingerprintRotation) pick() Fingerprint {
+	rotation.mu.Lock()
+	defer rotation.mu.Unlock()
+	profile := rotation.profiles[rotation.next]
+	rotation.next = (rotation.next + 1) % len(rotation.profiles)
+	return profile
+}
+
+// LoadFingerprints loads the list of fingerprints from the JSON file
+func LoadFingerprints(inDir string) ([]Fingerprint, error) {
+	profiles, err := ReadFromFile[Fingerprint](inDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		if profile.UserAgent == "" || profile.ClientPlatform == "" || len(profile.Brands) == 0 {
+			return nil, fmt.Errorf("Fingerprint %s must have the user agent, the platform and the brands", profile.Name)
+		}
+	}
+	return profiles, nil
+}
+
+// Identity of a tab, which is its fingerprint, the navigations made with it and its proxy
+type tabIdentity struct {
+	mu          sync.Mutex
+	fingerprint Fingerprint
+	navigations int
+	// Proxy of the tab's browser context, nil if the tab connects directly
+	proxy *proxyHealth
+}
+
+type identityKey struct{}
+
+// withIdentity applies a new fingerprint to the tab, the identity is kept in the tab's context
+func withIdentity(cdpCtx context.Context, proxy *proxyHealth) (context.Context, error) {
+	identity := &tabIdentity{fingerprint: getSession(cdpCtx).fingerprints.pick(), proxy: proxy}
+	if err := applyFingerprint(cdpCtx, identity.fingerprint); err != nil {
+		return nil, err
+	}
+	return context.WithValue(cdpCtx, identityKey{}, identity), nil
+}
+
+// countNavigation counts the tab's navigation and rotates its fingerprint when it's due
+func countNavigation(cdpCtx context.Context) error {
+	identity, ok := cdpCtx.Value(identityKey{}).(*tabIdentity)
+	if !ok {
+		return nil
+	}
+
+	fingerprints := getSession(cdpCtx).fingerprints
+	identity.mu.Lock()
+	defer identity.mu.Unlock()
+	identity.navigations += 1
+	if fingerprints.rotateEvery == 0 || identity.navigations <= fingerprints.rotateEvery {
+		return nil
+	}
+	identity.navigations = 1
+	identity.fingerprint = fingerprints.pick()
+	return applyFingerprint(cdpCtx, identity.fingerprint)
+}
+
+// rotateFingerprint switches the tab to the next fingerprint right away
+func rotateFingerprint(cdpCtx context.Context) error {
+	identity, ok := cdpCtx.Value(identityKey{}).(*tabIdentity)
+	if !ok {
+		return nil
+	}
+
+	identity.mu.Lock()
+	defer identity.mu.Unlock()
+	identity.navigations = 0
+	identity.fingerprint = getSession(cdpCtx).fingerprints.pick()
+	fmt.Printf("Switched to fingerprint %s\n", identity.fingerprint.Name)
+	return applyFingerprint(cdpCtx, identity.fingerprint)
+}
+
+// applyFingerprint emulates the fingerprint in the tab through CDP, so the headers
+// and what the page's scripts see agree with each other
+func applyFingerprint(cdpCtx context.Context, profile Fingerprint) error {
+	var brands, fullVersions []*emulation.UserAgentBrandVersion
+	for _, brand := range profile.Brands {
+		brands = append(brands, &emulation.UserAgentBrandVersion{Brand: brand.Brand, Version: brand.Version})
+		fullVersion := profile.FullVersion
+		if brand.Brand == "Not-A.Brand" {
+			fullVersion = brand.Version + ".0.0.0"
+		}
+		fullVersions = append(fullVersions, &emulation.UserAgentBrandVersion{Brand: brand.Brand, Version: fullVersion})
+	}
+	metadata := &emulation.UserAgentMetadata{
+		Brands:          brands,
+		FullVersionList: fullVersions,
+		Platform:        profile.ClientPlatform,
+		PlatformVersion: profile.PlatformVersion,
+		Architecture:    profile.Architecture,
+		Mobile:          profile.Mobile,
+	}
+
+	return chromedp.Run(cdpCtx,
+		network.Enable(),
+		network.SetExtraHTTPHeaders(network.Headers(getHeader())),
+		emulation.SetUserAgentOverride(profile.UserAgent).
+			WithAcceptLanguage(profile.AcceptLanguage).
+			WithPlatform(profile.Platform).
+			WithUserAgentMetadata(metadata),
+		emulation.SetDeviceMetricsOverride(
+			profile.Viewport.Width, profile.Viewport.Height,
+			profile.Viewport.DeviceScaleFactor, profile.Mobile,
+		),
+		// The overrides must be cleared before they are set again
+		emulation.SetLocaleOverride(),
+		emulation.SetLocaleOverride().WithLocale(profile.Locale),
+		emulation.SetTimezoneOverride(""),
+		emulation.SetTimezoneOverride(profile.Timezone),
+	)
+}
+
+// Generate the custom header sent with every navigation. The user agent, the language
+// and the client hints come from the fingerprint instead.
+func getHeader() map[string]any {
+	return map[string]any{
+		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8",
+		"Cache-Control":             "no-cache",
+		"Pragma":                    "no-cache",
+		"Upgrade-Insecure-Requests": "1",
+	}
+}
@@ -0,0 +1,121 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+)
+
+// File the results of the geocoders are cached in by default
+const DEFAULT_CACHE = "./data/geocode_cache.json"
+
+// Cache keeps the results of the geocoder on disk, so only the new or changed addresses
+// are geocoded again. The unmatched addresses are cached too, but not the failed ones.
+type Cache struct {
+	geocoder Geocoder
+	fileName string
+
+	mu sync.Mutex
+	// Results by the geocoder's name and the normalized address
+	results map[string]Result
+}
+
+// NewCache creates the cache of the geocoder's results, which are loaded from the JSON file if it exists.
+// Mapbox's results are only cached when it's permanent.
+func NewCache(geocoder Geocoder, fileName string) (*Cache, error) {
+	if mapbox, ok := geocoder.(*Mapbox); ok && !mapbox.Permanent {
+		return nil, fmt.Errorf("Mapbox's results can only be cached with permanent geocoding")
+	}
+	cache := &Cache{geocoder: geocoder, fileName: fileName, results: make(map[string]Result)}
+	jsonData, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to read geocode cache %s\n%w", fileName, err)
+	}
+	if err = json.Unmarshal(jsonData, &cache.results); err != nil {
+		return nil, fmt.Errorf("Failed to read geocode cache %s\n%w", fileName, err)
+	}
+	// The results cached before the match statuses are geocoded again
+	for key, result := range cache.results {
+		if result.Status == "" {
+			delete(cache.results, key)
+		}
+	}
+	return cache, nil
+}
+
+func (cache *Cache) Name() string {
+	return cache.geocoder.Name()
+}
+
+func (cache *Cache) Geocode(ctx context.Context, addresses []object.Address) ([]Result, error) {
+	results := make([]Result, len(addresses))
+	var missingAddresses []object.Address
+	var missingIndexes []int
+
+	cache.mu.Lock()
+	for i, address := range addresses {
+		if result, exists := cache.results[cache.key(address)]; exists {
+			results[i] = result
+		} else {
+			missingAddresses = append(missingAddresses, address)
+			missingIndexes = append(missingIndexes, i)
+		}
+	}
+	cache.mu.Unlock()
+
+	if len(missingAddresses) == 0 {
+		fmt.Printf("All of the %d addresses are geocoded already\n", len(addresses))
+		return results, nil
+	}
+	fmt.Printf("%d of the %d addresses are geocoded already\n", len(addresses)-len(missingAddresses), len(addresses))
+
+	missingResults, err := cache.geocoder.Geocode(ctx, missingAddresses)
+	if len(missingResults) != len(missingAddresses) {
+		if err == nil {
+			err = fmt.Errorf("%s returned %d results for %d addresses", cache.Name(), len(missingResults), len(missingAddresses))
+		}
+		return nil, err
+	}
+
+	// The results geocoded before an error are still cached
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for i, result := range missingResults {
+		results[missingIndexes[i]] = result
+		if result.Status != "" && result.Status != STATUS_FAILED {
+			cache.results[cache.key(missingAddresses[i])] = result
+		}
+	}
+	if saveErr := cache.save(); saveErr != nil {
+		// The addresses are still geocoded, they're only geocoded again next time
+		fmt.Printf("Failed to save geocode cache %s\n%s\n", cache.fileName, saveErr)
+	}
+	return results, err
+}
+
+// key gets the cache key of the address, e.g. "mapbox|123 main st, plano, tx 75080"
+func (cache *Cache) key(address object.Address) string {
+	return cache.geocoder.Name() + "|" + strings.Join(strings.Fields(strings.ToLower(formatAddress(address))), " ")
+}
+
+// save writes the results to the file, the lock must be held
+func (cache *Cache) save() error {
+	if err := os.MkdirAll(filepath.Dir(cache.fileName), 0755); err != nil {
+		return err
+	}
+	jsonData, err := json.Marshal(cache.results)
+	if err != nil {
+		return err
+	}
+	return scraper.WriteFileAtomic(cache.fileName, jsonData)
+}
@@ -0,0 +1,104 @@
+package geocode
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+const CENSUS_URL = "https://geocoding.geo.census.gov/geocoder/locations/address"
+
+type censusResponse struct {
+	Result struct {
+		AddressMatches []struct {
+			AddressComponents struct {
+				Zip string `json:"zip"`
+			} `json:"addressComponents"`
+			Coordinates struct {
+				X float32 `json:"x"`
+				Y float32 `json:"y"`
+			} `json:"coordinates"`
+		} `json:"addressMatches"`
+	} `json:"result"`
+}
+
+// Census geocodes the addresses one by one with the US Census Bureau's geocoder, which is free
+type Census struct {
+	// URL of the address lookup, CENSUS_URL if empty
+	BaseUrl string
+	// Client of the requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// NewCensus creates the Census geocoder
+func NewCensus() *Census {
+	return &Census{}
+}
+
+func (geocoder *Census) Name() string {
+	return "census"
+}
+
+func (geocoder *Census) Geocode(ctx context.Context, addresses []object.Address) ([]Result, error) {
+	// The addresses which aren't geocoded yet have no status
+	results := make([]Result, len(addresses))
+	for i, address := range addresses {
+		values := url.Values{}
+		values.Set("street", address.Street)
+		values.Set("city", address.City)
+		values.Set("state", address.State)
+		values.Set("zip", address.Zipcode)
+		values.Set("benchmark", "Public_AR_Current")
+		values.Set("format", "json")
+
+		censusResult, err := geocoder.get(ctx, cmp.Or(geocoder.BaseUrl, CENSUS_URL)+"?"+values.Encode())
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		} else if err != nil {
+			results[i] = failed(address, err)
+			continue
+		}
+		if matches := censusResult.Result.AddressMatches; len(matches) > 0 {
+			// The coordinates are the longitude as x and the latitude as y, which are
+			// interpolated along the street's address range
+			match := matches[0]
+			results[i] = Result{
+				Lat:      match.Coordinates.Y,
+				Lon:      match.Coordinates.X,
+				Status:   STATUS_MATCHED,
+				Accuracy: ACCURACY_INTERPOLATED,
+			}
+			if !sameZipcode(address.Zipcode, match.AddressComponents.Zip) {
+				results[i].Status = STATUS_PARTIAL
+			}
+		} else {
+			results[i].Status = STATUS_UNMATCHED
+		}
+	}
+	return results, nil
+}
+
+func (geocoder *Census) get(ctx context.Context, requestUrl string) (*censusResponse, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := cmp.Or(geocoder.Client, http.DefaultClient).Do(request)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readResponse(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var censusResult censusResponse
+	if err = json.Unmarshal(body, &censusResult); err != nil {
+		return nil, err
+	}
+	return &censusResult, nil
+}
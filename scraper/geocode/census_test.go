@@ -0,0 +1,55 @@
+package geocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+func TestCensusGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("benchmark") == "" || query.Get("format") != "json" {
+			t.Errorf("Query %s misses the benchmark or the format", r.URL.RawQuery)
+		}
+		switch query.Get("street") {
+		case "123 Main St":
+			w.Write([]byte(`{"result":{"addressMatches":[{"addressComponents":{"zip":"75080"},"coordinates":{"x":-96.75,"y":33.02}}]}}`))
+		case "456 Elm St":
+			w.Write([]byte(`{"result":{"addressMatches":[{"addressComponents":{"zip":"75081"},"coordinates":{"x":-96.7,"y":33}}]}}`))
+		case "broken":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"result":{"addressMatches":[]}}`))
+		}
+	}))
+	defer server.Close()
+
+	addresses := []object.Address{
+		{Street: "123 Main St", City: "Plano", State: "TX", Zipcode: "75080"},
+		{Street: "456 Elm St", City: "Plano", State: "TX", Zipcode: "75080"},
+		{Street: "broken", City: "Plano", State: "TX", Zipcode: "75080"},
+		{Street: "unknown", City: "Plano", State: "TX", Zipcode: "75080"},
+	}
+	geocoder := NewCensus()
+	geocoder.BaseUrl = server.URL
+	results, err := geocoder.Geocode(context.Background(), addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Result{
+		{Lat: 33.02, Lon: -96.75, Status: STATUS_MATCHED, Accuracy: ACCURACY_INTERPOLATED},
+		{Lat: 33, Lon: -96.7, Status: STATUS_PARTIAL, Accuracy: ACCURACY_INTERPOLATED},
+		{Status: STATUS_FAILED},
+		{Status: STATUS_UNMATCHED},
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("Result of address %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package geocode gets the coordinates of the parsed addresses from a geocoding service,
+// or from an offline file of ZIP code centroids
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+// Geocoder gets the coordinates of the addresses
+type Geocoder interface {
+	// Name of the geocoder, e.g. "mapbox"
+	Name() string
+	// Geocode gets the result of each address, in the same order as the addresses. On an error,
+	// the results geocoded so far can be returned with it, the others have no status.
+	Geocode(ctx context.Context, addresses []object.Address) ([]Result, error)
+}
+
+// Match statuses of the addresses
+const (
+	// The coordinates are of the address
+	STATUS_MATCHED = "matched"
+	// The coordinates are only near the address, e.g. of its street or ZIP code, or the
+	// matched address differs from it, e.g. in another ZIP code
+	STATUS_PARTIAL = "partial"
+	// The address isn't found, the coordinates are 0
+	STATUS_UNMATCHED = "unmatched"
+	// The address's request failed, so it's unmatched for now. It's not cached, so it's geocoded again.
+	STATUS_FAILED = "failed"
+)
+
+// Accuracies of the coordinates, from the most accurate
+const (
+	// The building of the address
+	ACCURACY_ROOFTOP = "rooftop"
+	// The parcel of the address
+	ACCURACY_PARCEL = "parcel"
+	// Interpolated between the known addresses of the street
+	ACCURACY_INTERPOLATED = "interpolated"
+	// Somewhere on the street of the address
+	ACCURACY_STREET = "street"
+	// Center of the address's ZIP code
+	ACCURACY_POSTCODE = "postcode"
+	// Center of the address's city or neighborhood
+	ACCURACY_PLACE = "place"
+)
+
+// Result of geocoding an address
+type Result struct {
+	Lat float32
+	Lon float32
+	// STATUS_MATCHED, STATUS_PARTIAL or STATUS_UNMATCHED
+	Status string
+	// Accuracy of the coordinates, e.g. ACCURACY_ROOFTOP, which is empty if it's unknown
+	Accuracy string
+	// Confidence of the match from 0 to 1, which is 0 if it's unknown
+	Relevance float32
+}
+
+// Matched checks if the result has the coordinates, which are only near the address if it's partial
+func (result Result) Matched() bool {
+	return result.Status == STATUS_MATCHED || result.Status == STATUS_PARTIAL
+}
+
+// failed gets the result of the address whose request failed, so the other addresses are still geocoded
+func failed(address object.Address, err error) Result {
+	fmt.Printf("Failed to geocode %s\n%s\n", formatAddress(address), err)
+	return Result{Status: STATUS_FAILED}
+}
+
+// sameZipcode checks if the ZIP codes are the same, ZIP+4 codes are compared by their first 5 digits.
+// They're the same if either is unknown.
+func sameZipcode(zipcode string, otherZipcode string) bool {
+	zipcode, _, _ = strings.Cut(strings.TrimSpace(zipcode), "-")
+	otherZipcode, _, _ = strings.Cut(strings.TrimSpace(otherZipcode), "-")
+	return zipcode == "" || otherZipcode == "" || zipcode == otherZipcode
+}
+
+// formatAddress formats the address like "123 Main St, Plano, TX 75080"
+func formatAddress(address object.Address) string {
+	return fmt.Sprintf("%s, %s, %s %s", address.Street, address.City, address.State, address.Zipcode)
+}
+
+// readResponse reads the body of the response, which must be 200
+func readResponse(response *http.Response) ([]byte, error) {
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ERROR: Non-200 status is returned, %s", response.Status)
+	}
+	return io.ReadAll(response.Body)
+}
@@ -0,0 +1,224 @@
+package geocode
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+const MAPBOX_URL = "https://api.mapbox.com/search/geocode/v6/batch"
+
+// Maximum addresses of Mapbox's batch request
+const MAPBOX_BATCH_LIMIT = 1000
+
+// Batch requests sent at the same time by default
+const MAPBOX_CONCURRENCY = 4
+
+// Relevance of Mapbox's confidences of the matches
+var MAPBOX_RELEVANCE = map[string]float32{"exact": 1, "high": 0.75, "medium": 0.5, "low": 0.25}
+
+type MapboxPayload struct {
+	Types []string `json:"types"`
+	Q     string   `json:"q"`
+	Limit int      `json:"limit"`
+}
+type Geometry struct {
+	Coordinates []float32 `json:"coordinates"`
+}
+type MatchCode struct {
+	AddressNumber string `json:"address_number"`
+	Street        string `json:"street"`
+	Postcode      string `json:"postcode"`
+	// Confidence of the match: exact, high, medium or low
+	Confidence string `json:"confidence"`
+}
+type Feature struct {
+	Geometry   Geometry `json:"geometry"`
+	Properties struct {
+		// Type of the matched feature, e.g. address, street or postcode
+		FeatureType string `json:"feature_type"`
+		Coordinates struct {
+			// Accuracy of the address's coordinates, e.g. rooftop, parcel or interpolated
+			Accuracy string `json:"accuracy"`
+		} `json:"coordinates"`
+		MatchCode MatchCode `json:"match_code"`
+	} `json:"properties"`
+}
+type BatchGeocodingResponse struct {
+	Batch []struct {
+		Type        string    `json:"type"`
+		Features    []Feature `json:"features"`
+		Attribution string    `json:"attribution"`
+	} `json:"batch"`
+}
+
+// Mapbox geocodes the addresses with Mapbox's batch geocoding, which needs an access token
+type Mapbox struct {
+	AccessToken string
+	// URL of the batch geocoding, MAPBOX_URL if empty
+	BaseUrl string
+	// Client of the requests, http.DefaultClient if nil
+	Client *http.Client
+	// Batch requests sent at the same time, the addresses are split into batches of MAPBOX_BATCH_LIMIT
+	Concurrency int
+	// The results are stored, e.g. in a Cache, which Mapbox's terms only allow for permanent geocoding
+	Permanent bool
+}
+
+// NewMapbox creates the Mapbox geocoder with the access token
+func NewMapbox(accessToken string) *Mapbox {
+	return &Mapbox{AccessToken: accessToken, Concurrency: MAPBOX_CONCURRENCY}
+}
+
+func (geocoder *Mapbox) Name() string {
+	return "mapbox"
+}
+
+func (geocoder *Mapbox) Geocode(ctx context.Context, addresses []object.Address) ([]Result, error) {
+	if geocoder.AccessToken == "" {
+		return nil, fmt.Errorf("Mapbox access token not available.")
+	}
+
+	// The batches are sent at the same time, each fills in its own part of the results.
+	// The other batches still go on when one fails, so their results can be cached.
+	results := make([]Result, len(addresses))
+	sem := make(chan struct{}, max(geocoder.Concurrency, 1))
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+	for start := 0; start < len(addresses); start += MAPBOX_BATCH_LIMIT {
+		end := min(start+MAPBOX_BATCH_LIMIT, len(addresses))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			if err := geocoder.geocodeBatch(ctx, addresses[start:end], results[start:end]); err != nil {
+				select {
+				case errs <- fmt.Errorf("Failed to geocode addresses %d to %d\n%w", start+1, end, err):
+				default:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		// The results of the finished batches are still returned
+		return results, err
+	default:
+	}
+	if ctx.Err() != nil {
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+// geocodeBatch geocodes the batch of at most MAPBOX_BATCH_LIMIT addresses into their results
+func (geocoder *Mapbox) geocodeBatch(ctx context.Context, addresses []object.Address, results []Result) error {
+	var payload []MapboxPayload
+	for _, address := range addresses {
+		payload = append(payload, MapboxPayload{
+			// The street or the ZIP code are matched when the address isn't found
+			Types: []string{"address", "street", "postcode"},
+			Q:     formatAddress(address),
+			Limit: 1,
+		})
+	}
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	values := url.Values{}
+	values.Set("access_token", geocoder.AccessToken)
+	if geocoder.Permanent {
+		values.Set("permanent", "true")
+	}
+	requestUrl := cmp.Or(geocoder.BaseUrl, MAPBOX_URL) + "?" + values.Encode()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, requestUrl, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := cmp.Or(geocoder.Client, http.DefaultClient).Do(request)
+	if err != nil {
+		return err
+	}
+	body, err := readResponse(response)
+	if err != nil {
+		return err
+	}
+
+	var batchResponse BatchGeocodingResponse
+	if err = json.Unmarshal(body, &batchResponse); err != nil {
+		return err
+	}
+	if len(batchResponse.Batch) != len(addresses) {
+		return fmt.Errorf("Mapbox returned %d results for %d addresses", len(batchResponse.Batch), len(addresses))
+	}
+
+	for i, batchResult := range batchResponse.Batch {
+		results[i] = mapboxResult(batchResult.Features)
+	}
+	return nil
+}
+
+// mapboxResult gets the result of the address from its features, it's partial when the feature
+// isn't the address, a part of the address isn't matched or the match has a low confidence
+func mapboxResult(features []Feature) Result {
+	if len(features) == 0 || len(features[0].Geometry.Coordinates) < 2 {
+		return Result{Status: STATUS_UNMATCHED}
+	}
+	feature := features[0]
+	matchCode := feature.Properties.MatchCode
+	result := Result{
+		Lon:       feature.Geometry.Coordinates[0],
+		Lat:       feature.Geometry.Coordinates[1],
+		Status:    STATUS_MATCHED,
+		Relevance: MAPBOX_RELEVANCE[matchCode.Confidence],
+	}
+
+	switch feature.Properties.FeatureType {
+	case "address":
+		switch accuracy := feature.Properties.Coordinates.Accuracy; accuracy {
+		case "rooftop", "point":
+			result.Accuracy = ACCURACY_ROOFTOP
+		case "parcel":
+			result.Accuracy = ACCURACY_PARCEL
+		case "interpolated":
+			result.Accuracy = ACCURACY_INTERPOLATED
+		case "intersection", "approximate":
+			result.Accuracy = ACCURACY_STREET
+		}
+	case "street":
+		result.Accuracy = ACCURACY_STREET
+		result.Status = STATUS_PARTIAL
+	case "postcode":
+		result.Accuracy = ACCURACY_POSTCODE
+		result.Status = STATUS_PARTIAL
+	default:
+		result.Accuracy = ACCURACY_PLACE
+		result.Status = STATUS_PARTIAL
+	}
+	for _, code := range []string{matchCode.AddressNumber, matchCode.Street, matchCode.Postcode} {
+		if code == "unmatched" {
+			result.Status = STATUS_PARTIAL
+		}
+	}
+	if matchCode.Confidence == "low" {
+		result.Status = STATUS_PARTIAL
+	}
+	return result
+}
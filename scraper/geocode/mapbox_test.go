@@ -0,0 +1,144 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+// mapboxFeature gets the JSON of a feature of Mapbox's batch response
+func mapboxFeature(featureType string, accuracy string, confidence string, postcode string) string {
+	return `{"geometry":{"coordinates":[-96.75,33.02]},"properties":{"feature_type":"` + featureType +
+		`","coordinates":{"accuracy":"` + accuracy + `"},"match_code":{"address_number":"matched",` +
+		`"street":"matched","postcode":"` + postcode + `","confidence":"` + confidence + `"}}}`
+}
+
+func TestMapboxResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		feature string
+		want    Result
+	}{
+		{"no features", "", Result{Status: STATUS_UNMATCHED}},
+		{
+			"rooftop address", mapboxFeature("address", "rooftop", "exact", "matched"),
+			Result{Lat: 33.02, Lon: -96.75, Status: STATUS_MATCHED, Accuracy: ACCURACY_ROOFTOP, Relevance: 1},
+		},
+		{
+			"interpolated address", mapboxFeature("address", "interpolated", "high", "matched"),
+			Result{Lat: 33.02, Lon: -96.75, Status: STATUS_MATCHED, Accuracy: ACCURACY_INTERPOLATED, Relevance: 0.75},
+		},
+		{
+			"address in another ZIP code", mapboxFeature("address", "parcel", "medium", "unmatched"),
+			Result{Lat: 33.02, Lon: -96.75, Status: STATUS_PARTIAL, Accuracy: ACCURACY_PARCEL, Relevance: 0.5},
+		},
+		{
+			"low confidence", mapboxFeature("address", "rooftop", "low", "matched"),
+			Result{Lat: 33.02, Lon: -96.75, Status: STATUS_PARTIAL, Accuracy: ACCURACY_ROOFTOP, Relevance: 0.25},
+		},
+		{
+			"postcode", mapboxFeature("postcode", "", "", ""),
+			Result{Lat: 33.02, Lon: -96.75, Status: STATUS_PARTIAL, Accuracy: ACCURACY_POSTCODE},
+		},
+		{
+			"street", mapboxFeature("street", "", "", ""),
+			Result{Lat: 33.02, Lon: -96.75, Status: STATUS_PARTIAL, Accuracy: ACCURACY_STREET},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var features []Feature
+			if err := json.Unmarshal([]byte("["+test.feature+"]"), &features); err != nil {
+				t.Fatal(err)
+			}
+			if got := mapboxResult(features); got != test.want {
+				t.Errorf("mapboxResult() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+// mapboxStub answers each address of the batches with a rooftop match, unless its street is "unknown"
+func mapboxStub(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("access_token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload []MapboxPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode the batch: %s", err)
+		}
+		if len(payload) > MAPBOX_BATCH_LIMIT {
+			t.Errorf("Batch of %d addresses is over the limit", len(payload))
+		}
+
+		var batch []string
+		for _, query := range payload {
+			if strings.HasPrefix(query.Q, "unknown") {
+				batch = append(batch, `{"features":[]}`)
+			} else {
+				batch = append(batch, `{"features":[`+mapboxFeature("address", "rooftop", "exact", "matched")+`]}`)
+			}
+		}
+		w.Write([]byte(`{"batch":[` + strings.Join(batch, ",") + `]}`))
+	}))
+}
+
+func TestMapboxGeocode(t *testing.T) {
+	var requests atomic.Int32
+	server := mapboxStub(t, &requests)
+	defer server.Close()
+
+	addresses := make([]object.Address, MAPBOX_BATCH_LIMIT*2+1)
+	for i := range addresses {
+		addresses[i] = object.Address{Street: "123 Main St", City: "Plano", State: "TX", Zipcode: "75080"}
+	}
+	addresses[MAPBOX_BATCH_LIMIT].Street = "unknown"
+
+	geocoder := NewMapbox("token")
+	geocoder.BaseUrl = server.URL
+	results, err := geocoder.Geocode(context.Background(), addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Sent %d batches, want 3", requests.Load())
+	}
+	if len(results) != len(addresses) {
+		t.Fatalf("Got %d results for %d addresses", len(results), len(addresses))
+	}
+	for i, result := range results {
+		wantStatus := STATUS_MATCHED
+		if i == MAPBOX_BATCH_LIMIT {
+			wantStatus = STATUS_UNMATCHED
+		}
+		if result.Status != wantStatus {
+			t.Errorf("Status of address %d = %s, want %s", i, result.Status, wantStatus)
+		}
+	}
+}
+
+func TestMapboxGeocodeErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := mapboxStub(t, &requests)
+	defer server.Close()
+	addresses := []object.Address{{Street: "123 Main St", City: "Plano", State: "TX", Zipcode: "75080"}}
+
+	if _, err := NewMapbox("").Geocode(context.Background(), addresses); err == nil {
+		t.Error("Geocoded without the access token")
+	}
+	geocoder := NewMapbox("wrong token")
+	geocoder.BaseUrl = server.URL
+	if _, err := geocoder.Geocode(context.Background(), addresses); err == nil {
+		t.Error("Geocoded with the wrong access token")
+	}
+}
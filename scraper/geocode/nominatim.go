@@ -0,0 +1,137 @@
+package geocode
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+const NOMINATIM_URL = "https://nominatim.openstreetmap.org/search"
+
+// Nominatim's usage policy allows one request per second
+const NOMINATIM_INTERVAL = time.Second
+
+type nominatimPlace struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+	// Rank of the place's address, 30 for the buildings and the house numbers and 26 to 27 for the streets
+	PlaceRank   int    `json:"place_rank"`
+	AddressType string `json:"addresstype"`
+	Type        string `json:"type"`
+	Address     struct {
+		Postcode string `json:"postcode"`
+	} `json:"address"`
+}
+
+// Nominatim geocodes the addresses one by one with OpenStreetMap's Nominatim, which is free
+type Nominatim struct {
+	// URL of the search, NOMINATIM_URL if empty
+	BaseUrl string
+	// User agent identifying the app, which Nominatim requires
+	UserAgent string
+	// Time between the requests, NOMINATIM_INTERVAL if 0
+	Interval time.Duration
+	// Client of the requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// NewNominatim creates the Nominatim geocoder identified by the user agent
+func NewNominatim(userAgent string) *Nominatim {
+	return &Nominatim{UserAgent: userAgent}
+}
+
+func (geocoder *Nominatim) Name() string {
+	return "nominatim"
+}
+
+func (geocoder *Nominatim) Geocode(ctx context.Context, addresses []object.Address) ([]Result, error) {
+	// The addresses which aren't geocoded yet have no status
+	results := make([]Result, len(addresses))
+	ticker := time.NewTicker(cmp.Or(geocoder.Interval, NOMINATIM_INTERVAL))
+	defer ticker.Stop()
+
+	for i, address := range addresses {
+		if i > 0 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return results, ctx.Err()
+			}
+		}
+
+		values := url.Values{}
+		values.Set("street", address.Street)
+		values.Set("city", address.City)
+		values.Set("state", address.State)
+		values.Set("postalcode", address.Zipcode)
+		values.Set("countrycodes", "us")
+		values.Set("format", "jsonv2")
+		values.Set("limit", "1")
+		values.Set("addressdetails", "1")
+
+		var places []nominatimPlace
+		err := geocoder.get(ctx, cmp.Or(geocoder.BaseUrl, NOMINATIM_URL)+"?"+values.Encode(), &places)
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		} else if err != nil {
+			results[i] = failed(address, err)
+			continue
+		}
+		if len(places) == 0 {
+			results[i].Status = STATUS_UNMATCHED
+			continue
+		}
+		lat, latErr := strconv.ParseFloat(places[0].Lat, 32)
+		lon, lonErr := strconv.ParseFloat(places[0].Lon, 32)
+		if latErr != nil || lonErr != nil {
+			results[i].Status = STATUS_UNMATCHED
+			continue
+		}
+		results[i] = nominatimResult(address, places[0], float32(lat), float32(lon))
+	}
+	return results, nil
+}
+
+// nominatimResult gets the result of the address from its place, it's partial when the
+// place isn't the building or the house number, or it's in another ZIP code
+func nominatimResult(address object.Address, place nominatimPlace, lat float32, lon float32) Result {
+	result := Result{Lat: lat, Lon: lon, Status: STATUS_PARTIAL}
+	switch {
+	case place.Type == "postcode" || place.AddressType == "postcode":
+		result.Accuracy = ACCURACY_POSTCODE
+	case place.PlaceRank >= 30:
+		result.Accuracy = ACCURACY_ROOFTOP
+		result.Status = STATUS_MATCHED
+	case place.PlaceRank >= 26:
+		result.Accuracy = ACCURACY_STREET
+	default:
+		result.Accuracy = ACCURACY_PLACE
+	}
+	if !sameZipcode(address.Zipcode, place.Address.Postcode) {
+		result.Status = STATUS_PARTIAL
+	}
+	return result
+}
+
+func (geocoder *Nominatim) get(ctx context.Context, requestUrl string, places *[]nominatimPlace) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("User-Agent", cmp.Or(geocoder.UserAgent, "useful-scraper"))
+	response, err := cmp.Or(geocoder.Client, http.DefaultClient).Do(request)
+	if err != nil {
+		return err
+	}
+	body, err := readResponse(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, places)
+}
@@ -0,0 +1,92 @@
+package geocode
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+func TestNominatimResult(t *testing.T) {
+	address := object.Address{Street: "123 Main St", City: "Plano", State: "TX", Zipcode: "75080"}
+	tests := []struct {
+		name  string
+		place nominatimPlace
+		want  Result
+	}{
+		{
+			"house number", nominatimPlace{PlaceRank: 30, Type: "house"},
+			Result{Status: STATUS_MATCHED, Accuracy: ACCURACY_ROOFTOP},
+		},
+		{
+			"street", nominatimPlace{PlaceRank: 26, Type: "residential"},
+			Result{Status: STATUS_PARTIAL, Accuracy: ACCURACY_STREET},
+		},
+		{
+			"postcode", nominatimPlace{PlaceRank: 21, Type: "postcode"},
+			Result{Status: STATUS_PARTIAL, Accuracy: ACCURACY_POSTCODE},
+		},
+		{
+			"city", nominatimPlace{PlaceRank: 16, Type: "city"},
+			Result{Status: STATUS_PARTIAL, Accuracy: ACCURACY_PLACE},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := nominatimResult(address, test.place, 0, 0); got != test.want {
+				t.Errorf("nominatimResult() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("another ZIP code", func(t *testing.T) {
+		place := nominatimPlace{PlaceRank: 30, Type: "house"}
+		place.Address.Postcode = "75081"
+		if got := nominatimResult(address, place, 0, 0); got.Status != STATUS_PARTIAL {
+			t.Errorf("Status = %s, want %s", got.Status, STATUS_PARTIAL)
+		}
+	})
+}
+
+func TestNominatimGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "test-agent" {
+			t.Errorf("User agent = %s, want test-agent", r.Header.Get("User-Agent"))
+		}
+		switch r.URL.Query().Get("street") {
+		case "123 Main St":
+			w.Write([]byte(`[{"lat":"33.02","lon":"-96.75","place_rank":30,"type":"house","address":{"postcode":"75080"}}]`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	addresses := []object.Address{
+		{Street: "123 Main St", City: "Plano", State: "TX", Zipcode: "75080"},
+		{Street: "broken", City: "Plano", State: "TX", Zipcode: "75080"},
+		{Street: "unknown", City: "Plano", State: "TX", Zipcode: "75080"},
+	}
+	geocoder := NewNominatim("test-agent")
+	geocoder.BaseUrl = server.URL
+	geocoder.Interval = 1
+	results, err := geocoder.Geocode(context.Background(), addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Result{
+		{Lat: 33.02, Lon: -96.75, Status: STATUS_MATCHED, Accuracy: ACCURACY_ROOFTOP},
+		{Status: STATUS_FAILED},
+		{Status: STATUS_UNMATCHED},
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("Result of address %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package geocode
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+// Column names of the ZIP codes and their centroids, e.g. the columns of the
+// Census Bureau's ZCTA gazetteer file
+var (
+	ZIP_COLUMNS = []string{"zip", "zipcode", "zip_code", "geoid", "zcta5"}
+	LAT_COLUMNS = []string{"lat", "latitude", "intptlat"}
+	LON_COLUMNS = []string{"lon", "lng", "longitude", "intptlong"}
+)
+
+// ZipCentroids geocodes the addresses offline to the centroids of their ZIP codes, which
+// are less accurate than the addresses' coordinates
+type ZipCentroids struct {
+	centroids map[string]Result
+}
+
+// LoadZipCentroids loads the centroids from the CSV or tab-separated file, like the
+// ZCTA gazetteer file of the TIGER data. The file must have a header with the columns
+// of the ZIP code, the latitude and the longitude, e.g. "zip,lat,lon".
+func LoadZipCentroids(fileName string) (*ZipCentroids, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if strings.HasSuffix(fileName, ".txt") || strings.HasSuffix(fileName, ".tsv") {
+		reader.Comma = '\t'
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read ZIP centroids %s\n%w", fileName, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("ZIP centroids %s is empty", fileName)
+	}
+
+	zipCol, latCol, lonCol := -1, -1, -1
+	for i, column := range records[0] {
+		column = strings.ToLower(strings.TrimSpace(column))
+		switch {
+		case slices.Contains(ZIP_COLUMNS, column):
+			zipCol = i
+		case slices.Contains(LAT_COLUMNS, column):
+			latCol = i
+		case slices.Contains(LON_COLUMNS, column):
+			lonCol = i
+		}
+	}
+	if zipCol < 0 || latCol < 0 || lonCol < 0 {
+		return nil, fmt.Errorf("ZIP centroids %s must have the columns of the ZIP code, latitude and longitude", fileName)
+	}
+
+	centroids := make(map[string]Result)
+	for _, record := range records[1:] {
+		if len(record) <= max(zipCol, latCol, lonCol) {
+			continue
+		}
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(record[latCol]), 32)
+		lon, lonErr := strconv.ParseFloat(strings.TrimSpace(record[lonCol]), 32)
+		if latErr != nil || lonErr != nil {
+			continue
+		}
+		// The centroid is only near the address
+		centroids[strings.TrimSpace(record[zipCol])] = Result{
+			Lat:      float32(lat),
+			Lon:      float32(lon),
+			Status:   STATUS_PARTIAL,
+			Accuracy: ACCURACY_POSTCODE,
+		}
+	}
+	return &ZipCentroids{centroids: centroids}, nil
+}
+
+func (geocoder *ZipCentroids) Name() string {
+	return "zip"
+}
+
+func (geocoder *ZipCentroids) Geocode(ctx context.Context, addresses []object.Address) ([]Result, error) {
+	results := make([]Result, len(addresses))
+	for i, address := range addresses {
+		// ZIP+4 codes are looked up by their first 5 digits
+		zipCode, _, _ := strings.Cut(address.Zipcode, "-")
+		result, exists := geocoder.centroids[zipCode]
+		if !exists {
+			result.Status = STATUS_UNMATCHED
+		}
+		results[i] = result
+	}
+	return results, nil
+}
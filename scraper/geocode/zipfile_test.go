@@ -0,0 +1,58 @@
+package geocode
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+func TestZipCentroids(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+	}{
+		{"CSV", "centroids.csv", "zip,lat,lon\n75080,32.97,-96.74\n"},
+		{"gazetteer", "2024_Gaz_zcta_national.txt", "GEOID\tALAND\tINTPTLAT\tINTPTLONG\n75080\t100\t32.97\t-96.74\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), test.fileName)
+			if err := os.WriteFile(fileName, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			geocoder, err := LoadZipCentroids(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := geocoder.Geocode(context.Background(), []object.Address{
+				{Zipcode: "75080"}, {Zipcode: "75080-1234"}, {Zipcode: "99999"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			centroid := Result{Lat: 32.97, Lon: -96.74, Status: STATUS_PARTIAL, Accuracy: ACCURACY_POSTCODE}
+			want := []Result{centroid, centroid, {Status: STATUS_UNMATCHED}}
+			for i := range want {
+				if results[i] != want[i] {
+					t.Errorf("Result of address %d = %+v, want %+v", i, results[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadZipCentroidsMissingColumns(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "centroids.csv")
+	if err := os.WriteFile(fileName, []byte("zip,lat\n75080,32.97\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadZipCentroids(fileName); err == nil {
+		t.Error("Loaded the centroids without the longitude column")
+	}
+}
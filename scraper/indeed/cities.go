@@ -0,0 +1,20 @@
+package indeed
+
+// Indeed locations of the supported cities, which can be changed by Options.Locations
+var DEFAULT_LOCATIONS = map[string]string{
+	"richardson":   "Richardson, TX",
+	"dallas":       "Dallas, TX",
+	"plano":        "Plano, TX",
+	"frisco":       "Frisco, TX",
+	"mckinney":     "McKinney, TX",
+	"irving":       "Irving, TX",
+	"allen":        "Allen, TX",
+	"southlake":    "Southlake, TX",
+	"coppell":      "Coppell, TX",
+	"flower-mound": "Flower Mound, TX",
+	"garland":      "Garland, TX",
+	"grapevine":    "Grapevine, TX",
+	"prosper":      "Prosper, TX",
+	"murphy":       "Murphy, TX",
+	"keller":       "Keller, TX",
+}
@@ -0,0 +1,134 @@
+package indeed
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Salary units as displayed by Indeed, e.g. "$25 an hour"
+var SALARY_UNITS = []string{"hour", "day", "week", "month", "year"}
+
+// ParseJobs parses the job postings of the HTML files saved under the directory.
+// A *scraper.PartialError is returned with the jobs when some of the files are skipped.
+func ParseJobs(ctx context.Context, dirName string) ([]*object.JobPosting, error) {
+	var jobPostings []*object.JobPosting
+	fmt.Println("Parsing job postings...")
+
+	parseErrs, err := scraper.ParseHTMLFiles(ctx, dirName, func(path string, htmlContent *goquery.Document) error {
+		jobPosting, err := ParseJobHTML(path, htmlContent)
+		if err != nil {
+			return err
+		}
+		jobPostings = append(jobPostings, jobPosting)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Parsed %d job postings completely!\n", len(jobPostings))
+	return jobPostings, scraper.NewPartialError(len(jobPostings), parseErrs)
+}
+
+// ParseJobHTML parses the job posting of the job's saved HTML,
+// the path is the path of the file or the page's link
+func ParseJobHTML(path string, htmlContent *goquery.Document) (*object.JobPosting, error) {
+	// Parse the value from the HTML doc
+	meta := htmlContent.Find(".job-meta")
+	jobUrl, _ := meta.Attr("data-url")
+	title := strings.TrimSpace(htmlContent.Find(".jobsearch-JobInfoHeader-title").Text())
+	if title == "" {
+		// Skip this job if it doesn't even have the title
+		return nil, &scraper.ParseError{Url: cmp.Or(jobUrl, path), Field: "title", Err: fmt.Errorf("Title missing, which is non-parsable")}
+	}
+	title = strings.TrimSpace(strings.TrimSuffix(title, "- job post"))
+
+	location := strings.TrimSpace(htmlContent.Find("[data-testid='inlineHeader-companyLocation']").Text())
+	applyUrl, exists := htmlContent.Find("#applyButtonLinkContainer a").Attr("href")
+	if !exists {
+		// Jobs without the external link are applied on Indeed
+		applyUrl = jobUrl
+	}
+
+	return &object.JobPosting{
+		Id:            primitive.NewObjectID(),
+		Title:         title,
+		Company:       strings.TrimSpace(htmlContent.Find("[data-testid='inlineHeader-companyName']").Text()),
+		Location:      location,
+		Salary:        getSalary(htmlContent.Find("#salaryInfoAndJobType").Text()),
+		WorkplaceType: getWorkplaceType(location),
+		PostedDate:    getPostedDate(meta.AttrOr("data-posted", ""), meta.AttrOr("data-scraped-at", "")),
+		Description:   strings.TrimSpace(htmlContent.Find("#jobDescriptionText").Text()),
+		ApplyUrl:      applyUrl,
+		Url:           jobUrl,
+	}, nil
+}
+
+// getSalary parses the text like "$50,000 - $70,000 a year" or "From $20 an hour"
+func getSalary(text string) object.PriceRange {
+	// The salary is displayed together with the job type, e.g. "$25 an hour - Full-time"
+	salaryText := strings.ToLower(strings.Split(text, " - Full")[0])
+	salaryText = strings.Split(salaryText, " - Part")[0]
+
+	unit := ""
+	for _, salaryUnit := range SALARY_UNITS {
+		if strings.Contains(salaryText, "a "+salaryUnit) || strings.Contains(salaryText, "an "+salaryUnit) {
+			unit = salaryUnit
+			break
+		}
+	}
+
+	salary := scraper.ParsePriceRange(salaryText, unit)
+	if strings.HasPrefix(strings.TrimSpace(salaryText), "up to") {
+		// Only the maximum is given
+		salary.Min = 0
+	}
+	return salary
+}
+
+// getWorkplaceType gets whether the job is remote, hybrid or on-site from its location
+func getWorkplaceType(location string) string {
+	lowerLocation := strings.ToLower(location)
+	switch {
+	case strings.Contains(lowerLocation, "hybrid"):
+		return "hybrid"
+	case strings.Contains(lowerLocation, "remote"):
+		return "remote"
+	default:
+		return "on-site"
+	}
+}
+
+// getPostedDate converts the relative posted date, e.g. "Posted 3 days ago",
+// to the date relative to when the job was scraped. The date like "2024-05-03" is kept.
+func getPostedDate(posted string, scrapedAt string) string {
+	if _, err := time.Parse(time.DateOnly, strings.TrimSpace(posted)); err == nil {
+		return strings.TrimSpace(posted)
+	}
+	scrapedTime, err := time.Parse(time.RFC3339, scrapedAt)
+	if err != nil {
+		return ""
+	}
+
+	lowerPosted := strings.ToLower(posted)
+	if strings.Contains(lowerPosted, "just posted") || strings.Contains(lowerPosted, "today") ||
+		strings.Contains(lowerPosted, "hour") || strings.Contains(lowerPosted, "minute") {
+		// Posted hours or minutes ago is counted as the same day
+		return scrapedTime.Format(time.DateOnly)
+	}
+
+	// "30+ days ago" is the oldest Indeed shows, so the date is at most 30 days ago
+	days := scraper.StrToInt32(scraper.NUMBER_REGEX.FindString(lowerPosted))
+	if days == 0 {
+		return ""
+	}
+	return scrapedTime.AddDate(0, 0, -int(days)).Format(time.DateOnly)
+}
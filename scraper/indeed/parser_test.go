@@ -0,0 +1,34 @@
+package indeed
+
+import "testing"
+
+func TestGetPostedDate(t *testing.T) {
+	scrapedAt := "2024-05-10T15:04:05Z"
+	tests := []struct {
+		posted string
+		want   string
+	}{
+		{"Just posted", "2024-05-10"},
+		{"Today", "2024-05-10"},
+		{"Posted 5 hours ago", "2024-05-10"},
+		{"Posted 1 hour ago", "2024-05-10"},
+		{"Posted 30 minutes ago", "2024-05-10"},
+		{"Posted 1 day ago", "2024-05-09"},
+		{"Posted 3 days ago", "2024-05-07"},
+		{"Posted 30+ days ago", "2024-04-10"},
+		{"2024-05-03", "2024-05-03"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.posted, func(t *testing.T) {
+			if got := getPostedDate(test.posted, scrapedAt); got != test.want {
+				t.Errorf("getPostedDate(%q) = %q, want %q", test.posted, got, test.want)
+			}
+		})
+	}
+
+	if got := getPostedDate("Posted 3 days ago", "yesterday"); got != "" {
+		t.Errorf("getPostedDate() without the scraped time = %q, want empty", got)
+	}
+}
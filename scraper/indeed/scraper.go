@@ -0,0 +1,182 @@
+/* Job openings scraper from Indeed.com */
+
+package indeed
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/mikehquan19/useful-scraper/scraper"
+)
+
+const INDEED_URL string = "https://www.indeed.com"
+
+// Number of job cards on each search page of Indeed
+const JOBS_PER_PAGE int = 10
+
+// JobCard is the job card of the search page, the posted date is only shown there
+type JobCard struct {
+	JobKey string `json:"jobKey"`
+	Posted string `json:"posted"`
+}
+
+// Collect the job key and the posted date of every job card on the search page
+const JOB_CARDS_SCRIPT string = `Array.from(document.querySelectorAll(".job_seen_beacon")).map(card => ({
+	jobKey: card.querySelector("a.jcs-JobTitle")?.dataset.jk ?? "",
+	posted: card.querySelector("[data-testid='myJobsStateDate'], .date")?.innerText ?? "",
+}))`
+
+func init() {
+	scraper.RegisterSource(New(Options{}))
+}
+
+// Options of the Indeed source
+type Options struct {
+	// Indeed locations of the cities by name, DEFAULT_LOCATIONS if nil
+	Locations map[string]string
+}
+
+// Source of the job openings on Indeed
+type Source struct {
+	opts Options
+}
+
+// New creates the Indeed source with the options
+func New(opts Options) *Source {
+	if opts.Locations == nil {
+		opts.Locations = DEFAULT_LOCATIONS
+	}
+	return &Source{opts: opts}
+}
+
+func (src *Source) Name() string {
+	return "indeed"
+}
+
+func (src *Source) ObjectType() string {
+	return "job"
+}
+
+func (src *Source) ListLinks(cdpCtx context.Context, query scraper.Query) ([]string, error) {
+	jobCards, err := src.GetJobCards(cdpCtx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// The posted date is only on the job cards, so it's carried in the link's fragment, e.g.
+	// "#posted=2024-05-03". It's saved with the links of the run, so the resumed runs still have it.
+	listedAt := time.Now().Format(time.RFC3339)
+	var jobLinks []string
+	for _, card := range jobCards {
+		jobLink := fmt.Sprintf("%s/viewjob?jk=%s", INDEED_URL, card.JobKey)
+		if posted := getPostedDate(card.Posted, listedAt); posted != "" {
+			jobLink += "#" + url.Values{"posted": {posted}}.Encode()
+		}
+		jobLinks = append(jobLinks, jobLink)
+	}
+	return jobLinks, nil
+}
+
+// DetailName gets the job key of the link
+func (src *Source) DetailName(link string) string {
+	jobUrl, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return jobUrl.Query().Get("jk")
+}
+
+func (src *Source) FetchDetail(cdpCtx context.Context, link string) ([]byte, error) {
+	jobUrl, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	fragment, _ := url.ParseQuery(jobUrl.Fragment)
+	jobUrl.Fragment = ""
+	return GetJobHTML(cdpCtx, jobUrl.String(), fragment.Get("posted"))
+}
+
+func (src *Source) Parse(ctx context.Context, dirName string) (any, error) {
+	return ParseJobs(ctx, dirName)
+}
+
+// GetJobCards gets the list of job cards matching the keyword in the city
+func (src *Source) GetJobCards(cdpCtx context.Context, query scraper.Query) ([]JobCard, error) {
+	location, exists := src.opts.Locations[strings.ToLower(query.City)]
+	if !exists {
+		return nil, fmt.Errorf("The city either doesn't exist or is not supported")
+	}
+
+	var jobCards []JobCard
+	fmt.Println("Scraping job links...")
+
+	// Search pages are offset by the start query, 10 jobs each
+	for start := 0; query.Limit == 0 || len(jobCards) < query.Limit; start += JOBS_PER_PAGE {
+		values := url.Values{}
+		values.Set("q", query.Keyword)
+		values.Set("l", location)
+		values.Set("start", fmt.Sprint(start))
+
+		var pageCards []JobCard
+		pageUrl := INDEED_URL + "/jobs?" + values.Encode()
+		_, err := scraper.Navigate(cdpCtx, pageUrl,
+			chromedp.WaitVisible("#mosaic-jobResults"),
+			chromedp.Evaluate(JOB_CARDS_SCRIPT, &pageCards),
+		)
+		if err != nil && start == 0 {
+			return nil, err
+		} else if err != nil {
+			// The next pages are unknown without this page, so keep the links scraped so far
+			fmt.Printf("Failed to scrape job links of %s\n%s\n", pageUrl, err)
+			scraper.RecordFailure(cdpCtx, pageUrl, err)
+			break
+		}
+
+		for _, card := range pageCards {
+			if card.JobKey != "" {
+				jobCards = append(jobCards, card)
+			}
+		}
+		if len(pageCards) < JOBS_PER_PAGE {
+			// This is the last page
+			break
+		}
+	}
+
+	fmt.Printf("Sucessfully scaped %d job links!\n", len(jobCards))
+	return jobCards, nil
+}
+
+// GetJobHTML navigates to the job's page and gets the HTML of its relevant sections, the posted
+// date is the date like "2024-05-03" or relative to now like "Posted 3 days ago"
+func GetJobHTML(cdpCtx context.Context, jobLink string, posted string) ([]byte, error) {
+	var header, description, applyButton string
+
+	_, err := scraper.Navigate(cdpCtx, jobLink,
+		chromedp.WaitVisible(".jobsearch-InfoHeaderContainer"),
+		chromedp.OuterHTML(".jobsearch-InfoHeaderContainer", &header, chromedp.ByQuery),
+
+		chromedp.WaitVisible("#jobDescriptionText"),
+		chromedp.OuterHTML("#jobDescriptionText", &description, chromedp.ByQuery),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = scraper.ExtractOrSkip(cdpCtx, "#applyButtonLinkContainer", "apply button", &applyButton)
+	if err != nil {
+		return nil, err
+	}
+
+	// The relative posted date is relative to when the job is scraped
+	return fmt.Appendf(nil,
+		`<div><div class="job-meta" data-url="%s" data-posted="%s" data-scraped-at="%s"></div>%s%s%s</div>`,
+		jobLink, html.EscapeString(posted), time.Now().Format(time.RFC3339),
+		header, description, applyButton,
+	), nil
+}
@@ -0,0 +1,43 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mikehquan19/useful-scraper/object"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UploadCars upserts the car info to the collection, keyed on their VIN or stock number
+func UploadCars(ctx context.Context, coll *mongo.Collection, carInfos []*object.CarInfo) (UploadResult, error) {
+	fmt.Printf("Uploading %d car infos...\n", len(carInfos))
+
+	var keys []string
+	for _, carInfo := range carInfos {
+		key, err := getCarKey(carInfo)
+		if err != nil {
+			return UploadResult{}, err
+		}
+		keys = append(keys, key)
+	}
+
+	result, err := bulkUpsert(ctx, coll, "car_key", keys, carInfos)
+	fmt.Printf(
+		"Inserted %d, updated %d, unchanged %d car infos\n",
+		result.Inserted, result.Updated, result.Unchanged,
+	)
+	return result, err
+}
+
+// getCarKey gets the natural key of the car, which is its VIN
+// or its CarMax stock number when the VIN is missing
+func getCarKey(carInfo *object.CarInfo) (string, error) {
+	if vin := strings.ToUpper(strings.TrimSpace(carInfo.Vin)); vin != "" {
+		return "vin:" + vin, nil
+	}
+	if stock := strings.TrimSpace(carInfo.StockNumber); stock != "" {
+		return "stock:" + stock, nil
+	}
+	return "", fmt.Errorf("Car %s %s has neither VIN nor stock number", carInfo.Make, carInfo.Model)
+}
@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+func TestGetCarKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		carInfo object.CarInfo
+		want    string
+		wantErr bool
+	}{
+		{"vin", object.CarInfo{Vin: " 1hgcm82633a004352 ", StockNumber: "26102431"}, "vin:1HGCM82633A004352", false},
+		{"stock number", object.CarInfo{StockNumber: " 26102431 "}, "stock:26102431", false},
+		{"neither", object.CarInfo{Make: "Honda", Model: "Accord"}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := getCarKey(&test.carInfo)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("getCarKey() error = %v, want error %t", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("getCarKey() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
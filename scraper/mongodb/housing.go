@@ -0,0 +1,50 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mikehquan19/useful-scraper/object"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UploadHouses upserts the housing info to the collection, keyed on their normalized address
+func UploadHouses(ctx context.Context, coll *mongo.Collection, homeInfos []*object.HomeInfo) (UploadResult, error) {
+	fmt.Printf("Uploading %d home infos...\n", len(homeInfos))
+
+	var keys []string
+	for _, homeInfo := range homeInfos {
+		keys = append(keys, getAddressKey(homeInfo.Address))
+	}
+
+	result, err := bulkUpsert(ctx, coll, "address_key", keys, homeInfos)
+	fmt.Printf(
+		"Inserted %d, updated %d, unchanged %d home infos\n",
+		result.Inserted, result.Updated, result.Unchanged,
+	)
+	return result, err
+}
+
+// getAddressKey gets the stable natural key of the home from its normalized address
+func getAddressKey(address object.Address) string {
+	streetWords := strings.Fields(normalizeKey(address.Street))
+	for i, word := range streetWords {
+		if abbr, ok := STREET_SUFFIXES[word]; ok {
+			streetWords[i] = abbr
+		}
+	}
+
+	// Only the 5-digit zip code is used as ZIP+4 isn't always listed
+	zipcode := normalizeKey(address.Zipcode)
+	if len(zipcode) > 5 {
+		zipcode = zipcode[:5]
+	}
+
+	return strings.Join([]string{
+		strings.Join(streetWords, " "),
+		normalizeKey(address.City),
+		normalizeKey(address.State),
+		zipcode,
+	}, "|")
+}
@@ -0,0 +1,45 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+func TestGetAddressKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		address object.Address
+		want    string
+	}{
+		{
+			"plain", object.Address{Street: "123 Main St", City: "Plano", State: "TX", Zipcode: "75080"},
+			"123 main st|plano|tx|75080",
+		},
+		{
+			"suffix spelled out", object.Address{Street: "123 Main Street", City: "Plano", State: "TX", Zipcode: "75080"},
+			"123 main st|plano|tx|75080",
+		},
+		{
+			"punctuation and spaces", object.Address{Street: " 456  Oak Blvd., Apt #2 ", City: "Richardson ", State: "tx", Zipcode: "75080"},
+			"456 oak blvd apt 2|richardson|tx|75080",
+		},
+		{
+			"zip+4", object.Address{Street: "789 Elm Dr", City: "Dallas", State: "TX", Zipcode: "75201-1234"},
+			"789 elm dr|dallas|tx|75201",
+		},
+		{
+			"several suffixes", object.Address{Street: "10 Park Place Drive", City: "Frisco", State: "TX", Zipcode: "75034"},
+			"10 park pl dr|frisco|tx|75034",
+		},
+		{"empty", object.Address{}, "|||"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := getAddressKey(test.address); got != test.want {
+				t.Errorf("getAddressKey(%+v) = %q, want %q", test.address, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var NON_ALNUM_REGEX = regexp.MustCompile(`[^a-z0-9 ]+`)
+
+// Common street suffixes, so "123 Main Street" and "123 Main St." share the same key
+var STREET_SUFFIXES = map[string]string{
+	"street":    "st",
+	"avenue":    "ave",
+	"drive":     "dr",
+	"road":      "rd",
+	"lane":      "ln",
+	"boulevard": "blvd",
+	"court":     "ct",
+	"parkway":   "pkwy",
+	"circle":    "cir",
+	"place":     "pl",
+	"trail":     "trl",
+	"terrace":   "ter",
+	"highway":   "hwy",
+}
+
+// Counts of the documents touched by a bulk upsert
+type UploadResult struct {
+	Inserted  int64
+	Updated   int64
+	Unchanged int64
+}
+
+// Connect connects to the MongoDB instance of the URI and makes sure it's reachable
+func Connect(ctx context.Context, uri string) (*mongo.Client, error) {
+	if uri == "" {
+		return nil, fmt.Errorf("MongoDB URI not available.")
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, err
+	}
+	if err = client.Ping(ctx, nil); err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("Failed to reach MongoDB\n%s", err)
+	}
+	return client, nil
+}
+
+// bulkUpsert upserts the documents keyed on keyField, so reruns update the existing documents
+// rather than duplicating them. keys[i] is the natural key of docs[i].
+func bulkUpsert[T any](ctx context.Context, coll *mongo.Collection, keyField string, keys []string, docs []T) (UploadResult, error) {
+	var result UploadResult
+	if len(keys) != len(docs) {
+		return result, fmt.Errorf("Got %d keys for %d documents", len(keys), len(docs))
+	}
+	if len(docs) == 0 {
+		return result, nil
+	}
+
+	// Make sure the natural key can't be duplicated by other writers
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: keyField, Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return result, fmt.Errorf("Failed to create index on %s\n%s", keyField, err)
+	}
+
+	var models []mongo.WriteModel
+	for i, doc := range docs {
+		fields, err := toBsonMap(doc)
+		if err != nil {
+			return result, err
+		}
+		fields[keyField] = keys[i]
+
+		// The id is generated on every parse, so it is only kept for the first insert
+		update := bson.M{"$set": fields}
+		if id, ok := fields["_id"]; ok {
+			delete(fields, "_id")
+			update["$setOnInsert"] = bson.M{"_id": id}
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{keyField: keys[i]}).
+			SetUpdate(update).
+			SetUpsert(true),
+		)
+	}
+
+	bulkResult, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if bulkResult != nil {
+		result.Inserted = bulkResult.UpsertedCount
+		result.Updated = bulkResult.ModifiedCount
+		result.Unchanged = bulkResult.MatchedCount - bulkResult.ModifiedCount
+	}
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) {
+			return result, describeBulkError(bulkErr, keys)
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+// describeBulkError lists every failed document of a partially failed bulk write
+func describeBulkError(bulkErr mongo.BulkWriteException, keys []string) error {
+	var lines []string
+	for _, writeErr := range bulkErr.WriteErrors {
+		key := "unknown"
+		if writeErr.Index < len(keys) {
+			key = keys[writeErr.Index]
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", key, writeErr.Message))
+	}
+	if bulkErr.WriteConcernError != nil {
+		lines = append(lines, fmt.Sprintf("  write concern: %s", bulkErr.WriteConcernError.Message))
+	}
+	return fmt.Errorf("%d documents failed to upload\n%s", len(bulkErr.WriteErrors), strings.Join(lines, "\n"))
+}
+
+// toBsonMap converts the object to a BSON map using its bson tags
+func toBsonMap(object any) (bson.M, error) {
+	data, err := bson.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var fields bson.M
+	if err = bson.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// normalizeKey lowercases the text, strips the punctuation and collapses the whitespaces
+func normalizeKey(text string) string {
+	text = NON_ALNUM_REGEX.ReplaceAllString(strings.ToLower(text), " ")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,145 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Status of the page after a navigation
+type PageStatus int
+
+const (
+	PAGE_OK PageStatus = iota
+	PAGE_NOT_FOUND
+	PAGE_BLOCKED
+)
+
+// Delay added to the domain's pace after it blocks, doubled on every block in a row
+const BLOCK_BACKOFF = 30 * time.Second
+const MAX_BLOCK_BACKOFF = 5 * time.Minute
+
+// Titles and texts of the Cloudflare, PerimeterX, Incapsula and captcha interstitials
+var CHALLENGE_MARKERS = []string{
+	"just a moment",
+	"attention required",
+	"checking your browser",
+	"verify you are human",
+	"are you a robot",
+	"press & hold",
+	"access to this page has been denied",
+	"pardon our interruption",
+	"request unsuccessful",
+}
+
+// Elements only found on the challenge pages
+var CHALLENGE_SELECTORS = []string{
+	"#challenge-form",
+	"#challenge-running",
+	"#cf-challenge-running",
+	"#px-captcha",
+	"iframe[src*='challenges.cloudflare.com']",
+}
+
+// Captcha widgets, which the contact forms of the listings also embed, so they're only
+// a challenge on a short page or along with a challenge marker
+var CAPTCHA_SELECTORS = []string{
+	".g-recaptcha",
+	".h-captcha",
+	"iframe[src*='captcha']",
+}
+
+// Texts of the challenge pages are short, longer pages are listings mentioning the markers
+const CHALLENGE_TEXT_LENGTH = 2000
+
+// Get the title, the beginning of the text and whether any challenge element is on the page
+const PAGE_SUMMARY_SCRIPT string = `({
+	title: document.title,
+	text: document.body ? document.body.innerText.slice(0, %d) : "",
+	textLength: document.body ? document.body.innerText.length : 0,
+	hasChallenge: document.querySelector(%q) !== null,
+	hasCaptcha: document.querySelector(%q) !== null,
+})`
+
+type pageSummary struct {
+	Title        string `json:"title"`
+	Text         string `json:"text"`
+	TextLength   int    `json:"textLength"`
+	HasChallenge bool   `json:"hasChallenge"`
+	HasCaptcha   bool   `json:"hasCaptcha"`
+}
+
+// classifyPage classifies the page by the response status and the page's content
+func classifyPage(cdpCtx context.Context, response *network.Response) (PageStatus, error) {
+	if response != nil {
+		switch {
+		case response.Status == 404 || response.Status == 410:
+			return PAGE_NOT_FOUND, nil
+		case response.Headers["cf-mitigated"] == "challenge":
+			return PAGE_BLOCKED, nil
+		}
+	}
+
+	var summary pageSummary
+	script := fmt.Sprintf(PAGE_SUMMARY_SCRIPT, CHALLENGE_TEXT_LENGTH,
+		strings.Join(CHALLENGE_SELECTORS, ", "), strings.Join(CAPTCHA_SELECTORS, ", "))
+	if err := chromedp.Run(cdpCtx, chromedp.Evaluate(script, &summary)); err != nil {
+		return PAGE_OK, err
+	}
+	if summary.HasChallenge || hasChallengeMarker(summary.Title) {
+		return PAGE_BLOCKED, nil
+	}
+	if summary.TextLength < CHALLENGE_TEXT_LENGTH && (summary.HasCaptcha || hasChallengeMarker(summary.Text)) {
+		return PAGE_BLOCKED, nil
+	}
+
+	// Bot protections also answer with 403 and 429 without showing a challenge
+	if response != nil && (response.Status == 403 || response.Status == 429) {
+		return PAGE_BLOCKED, nil
+	}
+	return PAGE_OK, nil
+}
+
+// isChallengeHTML checks if the scraped HTML is from a challenge page, so it's never saved as an object
+func isChallengeHTML(htmlContent []byte) bool {
+	lowerHTML := strings.ToLower(string(htmlContent))
+	for _, sel := range []string{"challenge-form", "challenge-running", "px-captcha"} {
+		if strings.Contains(lowerHTML, sel) {
+			return true
+		}
+	}
+	// Listings embed the captcha widgets in their contact forms
+	for _, sel := range []string{"g-recaptcha", "h-captcha"} {
+		if strings.Contains(lowerHTML, sel) && hasChallengeMarker(lowerHTML) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasChallengeMarker(text string) bool {
+	lowerText := strings.ToLower(text)
+	for _, marker := range CHALLENGE_MARKERS {
+		if strings.Contains(lowerText, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// resetIdentity clears the cookies and the cache of the browser, which are how the sites
+// remember the blocked visitor, and switches the tab to another fingerprint
+func resetIdentity(cdpCtx context.Context) error {
+	err := chromedp.Run(cdpCtx,
+		network.ClearBrowserCookies(),
+		network.ClearBrowserCache(),
+	)
+	if err != nil {
+		return err
+	}
+	return rotateFingerprint(cdpCtx)
+}
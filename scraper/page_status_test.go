@@ -0,0 +1,22 @@
+package scraper
+
+import "testing"
+
+func TestIsChallengeHTML(t *testing.T) {
+	tests := []struct {
+		html string
+		want bool
+	}{
+		{`<form id="challenge-form"></form>`, true},
+		{`<div id="px-captcha"></div>`, true},
+		{`<title>Just a moment...</title><div class="h-captcha"></div>`, true},
+		{`<h1>Verify you are human</h1><div class="g-recaptcha"></div>`, true},
+		{`<h1>2 bed apartment</h1><form class="contact"><div class="g-recaptcha"></div></form>`, false},
+		{`<h1>3 bed house</h1>`, false},
+	}
+	for _, test := range tests {
+		if got := isChallengeHTML([]byte(test.html)); got != test.want {
+			t.Errorf("isChallengeHTML(%q) = %v, want %v", test.html, got, test.want)
+		}
+	}
+}
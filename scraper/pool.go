@@ -0,0 +1,190 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Default number of tabs fetching the detail pages at the same time
+const DEFAULT_CONCURRENCY int = 3
+
+// detailPool fetches the detail pages with a pool of tabs sharing one browser
+type detailPool struct {
+	src   Source
+	state *runState
+	// Directory the pages are saved to, the pages aren't saved if it's empty
+	dirName string
+	limit   int
+	// Channel the fetched pages are sent to as they're fetched, if it's not nil
+	pageChan chan<- Page
+
+	mu sync.Mutex
+	// Number of fetched pages plus the pages being fetched, so the limit isn't overshot
+	reserved int
+	pages    []Page
+	failed   int
+}
+
+// run fetches the detail pages of the links with the given number of tabs
+func (pool *detailPool) run(cdpCtx context.Context, links []string, concurrency int) error {
+	tabCtxs := make([]context.Context, max(concurrency, 1))
+	tabCancels := make([]context.CancelFunc, len(tabCtxs))
+	defer func() {
+		for _, tabCancel := range tabCancels {
+			if tabCancel != nil {
+				tabCancel()
+			}
+		}
+	}()
+	for i := range tabCtxs {
+		tabCtx, tabCancel, err := newTab(cdpCtx)
+		if err != nil {
+			return fmt.Errorf("Failed to open tab\n%w", err)
+		}
+		tabCtxs[i], tabCancels[i] = tabCtx, tabCancel
+	}
+
+	linkChan := make(chan string)
+	var wg sync.WaitGroup
+	for i := range tabCtxs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range linkChan {
+				pool.fetch(tabCtxs[i], link)
+				if proxyIsBad(tabCtxs[i]) {
+					pool.reopenTab(cdpCtx, tabCtxs, tabCancels, i)
+				}
+			}
+		}()
+	}
+
+sendLinks:
+	for _, link := range links {
+		if pool.limitReached() {
+			break
+		}
+		select {
+		case linkChan <- link:
+		case <-cdpCtx.Done():
+			// The run is interrupted, the rest of the links are left for the next run
+			break sendLinks
+		}
+	}
+	close(linkChan)
+	wg.Wait()
+
+	return nil
+}
+
+// reopenTab replaces the tab whose proxy is marked bad with a tab using another proxy.
+// Each worker only touches its own tab, so the slices don't need the lock.
+func (pool *detailPool) reopenTab(cdpCtx context.Context, tabCtxs []context.Context, tabCancels []context.CancelFunc, i int) {
+	tabCtx, tabCancel, err := newTab(cdpCtx)
+	if err != nil {
+		fmt.Printf("Failed to reopen tab with another proxy\n%s\n", err)
+		return
+	}
+	tabCancels[i]()
+	tabCtxs[i], tabCancels[i] = tabCtx, tabCancel
+}
+
+// fetch fetches the detail page of the link and saves it, unless it's already in file storage
+func (pool *detailPool) fetch(tabCtx context.Context, link string) {
+	page := Page{Link: link, Name: pool.src.DetailName(link)}
+	if pool.dirName != "" {
+		page.Path = filepath.Join(pool.dirName, page.Name+".html")
+		_, err := os.Stat(page.Path)
+		if err == nil {
+			fmt.Println(page.Path + " exists!")
+			pool.state.finish(link, LINK_SAVED, nil)
+			return
+		} else if !os.IsNotExist(err) {
+			// No spot was reserved for the page, so only the failure is recorded
+			pool.state.finish(link, LINK_FAILED, err)
+			pool.mu.Lock()
+			pool.failed += 1
+			pool.mu.Unlock()
+			fmt.Printf("Failed to check %s\n%s\n", page.Path, err)
+			RecordFailure(tabCtx, link, err)
+			return
+		}
+	}
+
+	if !pool.reserve() {
+		return
+	}
+	pool.state.attempt(link)
+	htmlContent, err := pool.src.FetchDetail(tabCtx, link)
+	if err == nil && isChallengeHTML(htmlContent) {
+		// The challenge showed up after the page was loaded
+		err = fmt.Errorf("%s\n%w", link, ErrBlocked)
+	}
+	if err == nil && page.Path != "" {
+		err = WriteFileAtomic(page.Path, htmlContent)
+	}
+	if err != nil && tabCtx.Err() != nil {
+		// The run is interrupted, so the page is left for the next run
+		pool.release()
+		return
+	}
+	if err != nil {
+		pool.finish(link, nil, err)
+		fmt.Printf("Failed to save %s\n%s\n", link, err)
+		RecordFailure(tabCtx, link, err)
+		return
+	}
+	page.HTML = htmlContent
+	pool.finish(link, &page, nil)
+
+	if pool.pageChan != nil {
+		select {
+		case pool.pageChan <- page:
+		case <-tabCtx.Done():
+		}
+	}
+}
+
+// reserve reserves a spot for the page to be fetched, if the limit isn't reached
+func (pool *detailPool) reserve() bool {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if pool.limit > 0 && pool.reserved >= pool.limit {
+		return false
+	}
+	pool.reserved += 1
+	return true
+}
+
+// release gives back the spot of the page which isn't fetched
+func (pool *detailPool) release() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.reserved = max(pool.reserved-1, 0)
+}
+
+// finish records the fetched page of the link, or the failure with its error when the page is nil
+func (pool *detailPool) finish(link string, page *Page, err error) {
+	if page != nil {
+		pool.state.finish(link, LINK_SAVED, nil)
+	} else {
+		pool.state.finish(link, LINK_FAILED, err)
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if page != nil {
+		pool.pages = append(pool.pages, *page)
+	} else {
+		pool.failed += 1
+		pool.reserved = max(pool.reserved-1, 0)
+	}
+}
+
+func (pool *detailPool) limitReached() bool {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	return pool.limit > 0 && len(pool.pages) >= pool.limit
+}
@@ -0,0 +1,37 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestProxyPoolReport(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantSuccesses int
+		wantTimeouts  int
+		wantBlocks    int
+	}{
+		{"success", nil, 1, 0, 0},
+		{"not found", fmt.Errorf("link\n%w", ErrNotFound), 1, 0, 0},
+		{"blocked", fmt.Errorf("link\n%w", ErrBlocked), 0, 0, 1},
+		{"navigation timeout", context.DeadlineExceeded, 0, 1, 0},
+		{"network error", errors.New("page load error net::ERR_TIMED_OUT"), 0, 1, 0},
+		{"selector timeout", &SelectorError{Url: "link", Selector: ".price", Err: context.DeadlineExceeded}, 1, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool := &proxyPool{}
+			health := &proxyHealth{timeoutStreak: 1}
+			pool.report(health, test.err)
+			if health.successes != test.wantSuccesses || health.timeouts != test.wantTimeouts || health.blocks != test.wantBlocks {
+				t.Errorf("report(%v) = %d successes, %d timeouts, %d blocks, want %d, %d, %d", test.err,
+					health.successes, health.timeouts, health.blocks, test.wantSuccesses, test.wantTimeouts, test.wantBlocks)
+			}
+		})
+	}
+}
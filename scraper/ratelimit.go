@@ -0,0 +1,183 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// By default, each domain is navigated to once every 1.5 seconds
+var DEFAULT_RATE_LIMIT = RateLimit{Rate: 1 / 1.5, Burst: 1}
+
+const DEFAULT_JITTER = 500 * time.Millisecond
+
+// Rate limit of a domain as a token bucket
+type RateLimit struct {
+	// Number of navigations per second
+	Rate float64
+	// Number of navigations allowed in a row
+	Burst int
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+	// Number of blocks in a row by the domain
+	blocks int
+}
+
+// RateLimiter paces the navigations of all of the tabs by domain, with a random jitter
+// added to every delay so the navigations don't look scheduled
+type RateLimiter struct {
+	mu           sync.Mutex
+	defaultLimit RateLimit
+	// Rate limits by domain, e.g. "redfin.com" also limits "www.redfin.com"
+	limits  map[string]RateLimit
+	jitter  time.Duration
+	buckets map[string]*tokenBucket
+}
+
+// NewRateLimiter creates the rate limiter with the default limit and the limits by domain
+func NewRateLimiter(defaultLimit RateLimit, limits map[string]RateLimit, jitter time.Duration) *RateLimiter {
+	if limits == nil {
+		limits = make(map[string]RateLimit)
+	}
+	return &RateLimiter{
+		defaultLimit: defaultLimit,
+		limits:       limits,
+		jitter:       jitter,
+		buckets:      make(map[string]*tokenBucket),
+	}
+}
+
+// Wait waits until the link's domain can be navigated to again
+func (limiter *RateLimiter) Wait(ctx context.Context, link string) error {
+	linkUrl, err := url.Parse(link)
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(limiter.reserve(linkUrl.Hostname()))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the host's bucket and gets how long to wait for it
+func (limiter *RateLimiter) reserve(host string) time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	bucket := limiter.getBucket(host, now)
+	limit := bucket.limit
+
+	// Refill the tokens since the last reservation
+	elapsed := now.Sub(bucket.last).Seconds()
+	bucket.tokens = min(float64(limit.Burst), bucket.tokens+elapsed*limit.Rate)
+	bucket.last = now
+	bucket.tokens -= 1
+
+	var delay time.Duration
+	if bucket.tokens < 0 {
+		delay = time.Duration(-bucket.tokens / limit.Rate * float64(time.Second))
+	}
+	if limiter.jitter > 0 {
+		delay += rand.N(limiter.jitter)
+	}
+	return delay
+}
+
+// Penalize backs off the link's domain after it blocked the scraper, the backoff is doubled
+// on every block in a row
+func (limiter *RateLimiter) Penalize(link string) time.Duration {
+	linkUrl, err := url.Parse(link)
+	if err != nil {
+		return 0
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	bucket := limiter.getBucket(linkUrl.Hostname(), time.Now())
+	bucket.blocks += 1
+	backoff := min(BLOCK_BACKOFF<<(bucket.blocks-1), MAX_BLOCK_BACKOFF)
+
+	// Owe the tokens of the backoff, so the next navigations wait for it
+	bucket.tokens -= backoff.Seconds() * bucket.limit.Rate
+	return backoff
+}
+
+// Forgive resets the backoff of the link's domain after it served a page
+func (limiter *RateLimiter) Forgive(link string) {
+	linkUrl, err := url.Parse(link)
+	if err != nil {
+		return
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.getBucket(linkUrl.Hostname(), time.Now()).blocks = 0
+}
+
+// getBucket gets the bucket of the host's domain, a full bucket is created for the new domain
+func (limiter *RateLimiter) getBucket(host string, now time.Time) *tokenBucket {
+	domain, limit := limiter.getLimit(host)
+	bucket, exists := limiter.buckets[domain]
+	if !exists {
+		bucket = &tokenBucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		limiter.buckets[domain] = bucket
+	}
+	return bucket
+}
+
+// getLimit gets the domain the host belongs to and its rate limit
+func (limiter *RateLimiter) getLimit(host string) (string, RateLimit) {
+	for domain, limit := range limiter.limits {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return domain, limit
+		}
+	}
+	return host, limiter.defaultLimit
+}
+
+// ParseRateLimits parses the rate limits like "redfin.com=0.5,carmax.com=1:3",
+// which are the navigations per second and optionally the burst of each domain
+func ParseRateLimits(spec string) (map[string]RateLimit, error) {
+	limits := make(map[string]RateLimit)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		domain, value, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("Rate limit %s must be like domain=rate[:burst]", item)
+		}
+
+		rateText, burstText, hasBurst := strings.Cut(value, ":")
+		rate, err := strconv.ParseFloat(rateText, 64)
+		if err != nil || rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+			return nil, fmt.Errorf("Rate of %s must be a positive number", domain)
+		}
+		burst := 1
+		if hasBurst {
+			burst, err = strconv.Atoi(burstText)
+			if err != nil || burst < 1 {
+				return nil, fmt.Errorf("Burst of %s must be a positive integer", domain)
+			}
+		}
+		limits[strings.ToLower(strings.TrimSpace(domain))] = RateLimit{Rate: rate, Burst: burst}
+	}
+	return limits, nil
+}
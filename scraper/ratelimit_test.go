@@ -0,0 +1,42 @@
+package scraper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string]RateLimit
+		wantErr bool
+	}{
+		{"", map[string]RateLimit{}, false},
+		{"redfin.com=0.5", map[string]RateLimit{"redfin.com": {Rate: 0.5, Burst: 1}}, false},
+		{
+			" Redfin.com=0.5, carmax.com=1:3 ,",
+			map[string]RateLimit{"redfin.com": {Rate: 0.5, Burst: 1}, "carmax.com": {Rate: 1, Burst: 3}},
+			false,
+		},
+		{"redfin.com", nil, true},
+		{"redfin.com=fast", nil, true},
+		{"redfin.com=0", nil, true},
+		{"redfin.com=NaN", nil, true},
+		{"redfin.com=Inf", nil, true},
+		{"redfin.com=+Inf:2", nil, true},
+		{"redfin.com=1:0", nil, true},
+		{"redfin.com=1:many", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			got, err := ParseRateLimits(test.spec)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseRateLimits(%q) error = %v, want error %t", test.spec, err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseRateLimits(%q) = %v, want %v", test.spec, got, test.want)
+			}
+		})
+	}
+}
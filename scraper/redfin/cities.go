@@ -1,6 +1,7 @@
-package internal
+package redfin
 
-var HREF_MAP = map[string]string{
+// Redfin paths of the supported cities, which can be changed by Options.CityHrefs
+var DEFAULT_CITY_HREFS = map[string]string{
 	"richardson":   "/city/30861/TX/Richardson",
 	"dallas":       "/city/30794/TX/Dallas",
 	"plano":        "/city/30868/TX/Plano",
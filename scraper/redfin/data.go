@@ -0,0 +1,297 @@
+package redfin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/chromedp/chromedp"
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Extraction modes of the home's page
+const (
+	// The sections of the page are saved and parsed with selectors
+	EXTRACT_HTML = "html"
+	// The structured data of the page is saved, the sections are saved when there's none
+	EXTRACT_DATA = "data"
+)
+
+// Path of the stingray API calls of the home's page, e.g. aboveTheFold and belowTheFold
+const STINGRAY_DETAILS_PATH = "/stingray/api/home/details/"
+
+// Collect the JSON-LD of the page
+const JSON_LD_SCRIPT = `Array.from(document.querySelectorAll('script[type="application/ld+json"]')).map(s => s.textContent)`
+
+// GetHomeData navigates to the house's page and gets its structured data, which is the page's
+// JSON-LD and the responses of the stingray API captured through CDP. The HTML of the relevant
+// sections is got instead when the page has no structured data.
+func GetHomeData(cdpCtx context.Context, homeLink string) ([]byte, error) {
+	capture, stopCapture := scraper.CaptureResponses(cdpCtx, func(url string) bool {
+		return strings.Contains(url, STINGRAY_DETAILS_PATH)
+	})
+	defer stopCapture()
+
+	var jsonLds []string
+	_, err := scraper.Navigate(cdpCtx, homeLink,
+		chromedp.WaitVisible(".AddressBannerV2"),
+		chromedp.Evaluate(JSON_LD_SCRIPT, &jsonLds),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The details below the fold, like the property history, are loaded when they're scrolled to
+	err = chromedp.Run(cdpCtx, chromedp.Evaluate(`window.scrollTo(0, document.body.scrollHeight)`, nil))
+	if err != nil {
+		return nil, err
+	}
+	if !capture.WaitFor(cdpCtx, func(url string) bool {
+		return strings.Contains(url, "belowTheFold")
+	}, scraper.EXTRACT_TIMEOUT) {
+		fmt.Println("Details below the fold not available in the website")
+	}
+	bodies, err := capture.Bodies(cdpCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jsonLds) == 0 && len(bodies) == 0 {
+		fmt.Printf("No structured data in %s, getting its HTML instead\n", homeLink)
+		return GetHomeHTML(cdpCtx, homeLink)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, `<div class="redfin-data" data-url="%s">`, html.EscapeString(homeLink))
+	for _, jsonLd := range jsonLds {
+		fmt.Fprintf(&builder, `<script type="application/ld+json">%s</script>`, escapeScript(jsonLd))
+	}
+	for apiUrl, body := range bodies {
+		fmt.Fprintf(&builder,
+			`<script type="application/json" data-api="%s">%s</script>`,
+			html.EscapeString(apiName(apiUrl)), escapeScript(strings.TrimPrefix(string(body), "{}&&")),
+		)
+	}
+	builder.WriteString("</div>")
+	return []byte(builder.String()), nil
+}
+
+// escapeScript escapes the JSON, so it can't close its script tag
+func escapeScript(jsonText string) string {
+	return strings.ReplaceAll(jsonText, "</", `<\/`)
+}
+
+// apiName gets the name of the stingray API call, e.g. "belowTheFold"
+func apiName(apiUrl string) string {
+	parsedUrl, err := url.Parse(apiUrl)
+	if err != nil {
+		return apiUrl
+	}
+	return path.Base(parsedUrl.Path)
+}
+
+// parseHomeData parses the housing info of the house's saved structured data. The JSON-LD
+// is parsed first, then the responses of the stingray API fill in and correct it.
+func parseHomeData(url string, htmlContent *goquery.Document) (*object.HomeInfo, error) {
+	parseErr := func(field string, err error) error {
+		return &scraper.ParseError{Url: url, Field: field, Err: err}
+	}
+	homeInfo := &object.HomeInfo{Id: primitive.NewObjectID()}
+
+	var parseErrs []error
+	htmlContent.Find(`.redfin-data script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var jsonLd any
+		if err := json.Unmarshal([]byte(s.Text()), &jsonLd); err != nil {
+			parseErrs = append(parseErrs, parseErr("JSON-LD", err))
+			return
+		}
+		applyJsonLd(homeInfo, jsonLd)
+	})
+	htmlContent.Find(`.redfin-data script[data-api]`).Each(func(i int, s *goquery.Selection) {
+		var response struct {
+			Payload map[string]any `json:"payload"`
+		}
+		if err := json.Unmarshal([]byte(s.Text()), &response); err != nil {
+			parseErrs = append(parseErrs, parseErr(s.AttrOr("data-api", "stingray API"), err))
+			return
+		}
+		applyStingray(homeInfo, response.Payload)
+	})
+
+	address := homeInfo.Address
+	if address.Street == "" || address.City == "" || address.State == "" || address.Zipcode == "" {
+		if len(parseErrs) > 0 {
+			return nil, parseErrs[0]
+		}
+		return nil, parseErr("address", fmt.Errorf("Address missing info, which is non-parsable"))
+	}
+	if homeInfo.Price <= 0 {
+		return nil, parseErr("price", fmt.Errorf("Price missing, which is non-parsable"))
+	}
+	homeInfo.Status = getStatus(htmlContent, homeInfo.History)
+	return homeInfo, nil
+}
+
+// applyJsonLd fills in the housing info from the JSON-LD nodes, e.g. the SingleFamilyResidence
+// inside the RealEstateListing. Only the fields which aren't filled in yet are set.
+func applyJsonLd(homeInfo *object.HomeInfo, jsonLd any) {
+	switch node := jsonLd.(type) {
+	case []any:
+		for _, child := range node {
+			applyJsonLd(homeInfo, child)
+		}
+	case map[string]any:
+		if address, ok := node["address"].(map[string]any); ok && homeInfo.Address.Street == "" {
+			homeInfo.Address = object.Address{
+				Street:  jsonString(address["streetAddress"]),
+				City:    jsonString(address["addressLocality"]),
+				State:   jsonString(address["addressRegion"]),
+				Zipcode: jsonString(address["postalCode"]),
+			}
+			if description := jsonString(node["description"]); homeInfo.Description == "" {
+				homeInfo.Description = description
+			}
+		}
+		if geo, ok := node["geo"].(map[string]any); ok && homeInfo.Lat == 0 && homeInfo.Lon == 0 {
+			homeInfo.Lat = jsonNumber(geo["latitude"])
+			homeInfo.Lon = jsonNumber(geo["longitude"])
+		}
+		if homeInfo.Bedrooms == 0 {
+			homeInfo.Bedrooms = jsonNumber(node["numberOfBedrooms"])
+		}
+		if homeInfo.Bathrooms == 0 {
+			homeInfo.Bathrooms = jsonNumber(node["numberOfBathroomsTotal"])
+		}
+		if floorSize, ok := node["floorSize"].(map[string]any); ok && homeInfo.HomeArea.Value == 0 {
+			homeInfo.HomeArea = object.Area{Unit: "sqft", Value: jsonNumber(floorSize["value"])}
+		}
+		if yearBuilt := jsonString(node["yearBuilt"]); homeInfo.YearBuilt == "" {
+			homeInfo.YearBuilt = yearBuilt
+		}
+		if offers, ok := node["offers"].(map[string]any); ok && homeInfo.Price == 0 {
+			homeInfo.Price = jsonNumber(offers["price"])
+		}
+		for _, child := range node {
+			applyJsonLd(homeInfo, child)
+		}
+	}
+}
+
+// applyStingray fills in the housing info from the payload of a stingray API call.
+// The API's values are more accurate than the JSON-LD, so they replace them.
+func applyStingray(homeInfo *object.HomeInfo, payload map[string]any) {
+	if info, ok := jsonPath(payload, "addressSectionInfo").(map[string]any); ok {
+		if street := jsonString(jsonPath(info, "streetAddress", "assembledAddress")); street != "" {
+			homeInfo.Address = object.Address{
+				Street:  street,
+				City:    jsonString(info["city"]),
+				State:   jsonString(info["state"]),
+				Zipcode: jsonString(info["zip"]),
+			}
+		}
+		setNumber(&homeInfo.Bedrooms, info["beds"])
+		setNumber(&homeInfo.Bathrooms, info["baths"])
+		setNumber(&homeInfo.Price, jsonPath(info, "priceInfo", "amount"))
+		setNumber(&homeInfo.PricePerUnit, jsonPath(info, "pricePerSqFt"))
+		if sqFt := jsonNumber(jsonPath(info, "sqFt", "value")); sqFt > 0 {
+			homeInfo.HomeArea = object.Area{Unit: "sqft", Value: sqFt}
+		}
+		if yearBuilt := jsonString(info["yearBuilt"]); yearBuilt != "" {
+			homeInfo.YearBuilt = yearBuilt
+		}
+		setNumber(&homeInfo.Lat, jsonPath(info, "latLong", "value", "latitude"))
+		setNumber(&homeInfo.Lon, jsonPath(info, "latLong", "value", "longitude"))
+	}
+
+	if remarks, ok := jsonPath(payload, "mainHouseInfo", "marketingRemarks").([]any); ok && len(remarks) > 0 {
+		if remark := jsonString(jsonPath(remarks[0], "marketingRemark")); remark != "" {
+			homeInfo.Description = remark
+		}
+	}
+	if propertyType := jsonString(jsonPath(payload, "publicRecordsInfo", "basicInfo", "propertyTypeName")); propertyType != "" {
+		homeInfo.PropertyType = propertyType
+	}
+
+	if events, ok := jsonPath(payload, "propertyHistoryInfo", "events").([]any); ok {
+		homeInfo.History = nil
+		for _, event := range events {
+			description := jsonString(jsonPath(event, "eventDescription"))
+			homeInfo.History = append(homeInfo.History, object.PriceEvent{
+				Date:   jsonDate(jsonPath(event, "eventDate")),
+				Event:  classifyEvent(description),
+				Price:  jsonNumber(jsonPath(event, "price")),
+				Source: jsonString(jsonPath(event, "sourceDescription")),
+			})
+		}
+	}
+
+	if schools, ok := jsonPath(payload, "schoolsAndDistrictsInfo", "servingThisHomeSchools").([]any); ok {
+		homeInfo.Schools = nil
+		for _, school := range schools {
+			homeInfo.Schools = append(homeInfo.Schools, object.School{
+				Name:     jsonString(jsonPath(school, "name")),
+				Type:     jsonString(jsonPath(school, "institutionType")),
+				Distance: fmt.Sprintf("%.1fmi", jsonNumber(jsonPath(school, "distanceInMiles"))),
+			})
+		}
+	}
+}
+
+// jsonPath gets the value at the keys of the nested JSON objects, which is nil if it's missing
+func jsonPath(value any, keys ...string) any {
+	for _, key := range keys {
+		node, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = node[key]
+	}
+	return value
+}
+
+// jsonString gets the JSON string or number as a string
+func jsonString(value any) string {
+	switch value := value.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+// jsonNumber gets the JSON number or numeric string as a number, which is 0 if it's not numeric
+func jsonNumber(value any) float32 {
+	switch value := value.(type) {
+	case float64:
+		return float32(value)
+	case string:
+		return scraper.StrToFloat32(strings.TrimPrefix(strings.TrimSpace(value), "$"))
+	}
+	return 0
+}
+
+// setNumber sets the field to the JSON number, unless it's missing
+func setNumber(field *float32, value any) {
+	if number := jsonNumber(value); number != 0 {
+		*field = number
+	}
+}
+
+// jsonDate gets the date of the timestamp in milliseconds like "2024-05-03"
+func jsonDate(value any) string {
+	millis, ok := value.(float64)
+	if !ok {
+		return jsonString(value)
+	}
+	return time.UnixMilli(int64(millis)).UTC().Format(time.DateOnly)
+}
@@ -0,0 +1,191 @@
+package redfin
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Statuses of the searched homes
+const (
+	STATUS_FOR_SALE = "for-sale"
+	STATUS_SOLD     = "sold"
+	// Homes under contract, which are contingent or pending
+	STATUS_PENDING = "pending"
+)
+
+// Property types of Redfin's search
+var PROPERTY_TYPES = []string{"house", "condo", "townhouse", "multifamily", "land", "manufactured", "co-op", "other"}
+
+// Periods of Redfin's search for the days on market and the sold homes
+var SEARCH_PERIODS = []string{"1d", "3d", "1wk", "2wk", "1mo", "3mo", "6mo", "1yr", "2yr", "3yr", "5yr"}
+
+// Period the sold homes are searched in when it's not given
+const DEFAULT_SOLD_WITHIN = "3mo"
+
+// Filters of Redfin's search, the zero values aren't filtered
+type Filters struct {
+	MinPrice      int      `json:"minPrice,omitempty"`
+	MaxPrice      int      `json:"maxPrice,omitempty"`
+	MinBeds       int      `json:"minBeds,omitempty"`
+	MaxBeds       int      `json:"maxBeds,omitempty"`
+	MinBaths      float32  `json:"minBaths,omitempty"`
+	PropertyTypes []string `json:"propertyTypes,omitempty"`
+	MinSqft       int      `json:"minSqft,omitempty"`
+	MaxSqft       int      `json:"maxSqft,omitempty"`
+	// STATUS_FOR_SALE if empty
+	Status string `json:"status,omitempty"`
+	// Period the sold homes are sold in, e.g. "6mo", DEFAULT_SOLD_WITHIN if empty
+	SoldWithin string `json:"soldWithin,omitempty"`
+	// Period the homes are on the market at most, e.g. "1wk"
+	MaxDaysOnMarket string `json:"maxDaysOnMarket,omitempty"`
+}
+
+// ParseFilters parses the filters like "min-price=300000,min-beds=3,property-type=house+condo,status=sold"
+func ParseFilters(text string) (Filters, error) {
+	var filters Filters
+	if strings.TrimSpace(text) == "" {
+		return filters, nil
+	}
+
+	for _, part := range strings.Split(text, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found || value == "" {
+			return Filters{}, fmt.Errorf("Invalid filter %q, it must be like min-price=300000", part)
+		}
+
+		var err error
+		switch key {
+		case "min-price":
+			filters.MinPrice, err = strconv.Atoi(value)
+		case "max-price":
+			filters.MaxPrice, err = strconv.Atoi(value)
+		case "min-beds":
+			filters.MinBeds, err = strconv.Atoi(value)
+		case "max-beds":
+			filters.MaxBeds, err = strconv.Atoi(value)
+		case "min-baths":
+			var baths float64
+			baths, err = strconv.ParseFloat(value, 32)
+			filters.MinBaths = float32(baths)
+		case "property-type":
+			filters.PropertyTypes = strings.Split(value, "+")
+		case "min-sqft":
+			filters.MinSqft, err = strconv.Atoi(value)
+		case "max-sqft":
+			filters.MaxSqft, err = strconv.Atoi(value)
+		case "status":
+			filters.Status = value
+		case "sold-within":
+			filters.SoldWithin = value
+		case "max-days-on-market":
+			filters.MaxDaysOnMarket = value
+		default:
+			return Filters{}, fmt.Errorf("Filter %s is not supported", key)
+		}
+		if err != nil {
+			return Filters{}, fmt.Errorf("Invalid value of filter %s\n%w", key, err)
+		}
+	}
+	return filters, filters.validate()
+}
+
+func (filters Filters) validate() error {
+	if filters.MinPrice < 0 || filters.MaxPrice < 0 || filters.MinBeds < 0 || filters.MaxBeds < 0 ||
+		filters.MinBaths < 0 || filters.MinSqft < 0 || filters.MaxSqft < 0 {
+		return fmt.Errorf("Filters can't be negative")
+	}
+	if filters.MaxPrice > 0 && filters.MinPrice > filters.MaxPrice {
+		return fmt.Errorf("Minimum price is more than the maximum price")
+	}
+	if filters.MaxBeds > 0 && filters.MinBeds > filters.MaxBeds {
+		return fmt.Errorf("Minimum beds are more than the maximum beds")
+	}
+	if filters.MaxSqft > 0 && filters.MinSqft > filters.MaxSqft {
+		return fmt.Errorf("Minimum sqft is more than the maximum sqft")
+	}
+	for _, propertyType := range filters.PropertyTypes {
+		if !slices.Contains(PROPERTY_TYPES, propertyType) {
+			return fmt.Errorf("Property type %s is not one of %s", propertyType, strings.Join(PROPERTY_TYPES, ", "))
+		}
+	}
+	switch filters.Status {
+	case "", STATUS_FOR_SALE, STATUS_SOLD, STATUS_PENDING:
+	default:
+		return fmt.Errorf("Status %s is not one of for-sale, sold, pending", filters.Status)
+	}
+	if filters.SoldWithin != "" && filters.Status != STATUS_SOLD {
+		return fmt.Errorf("Sold within is only for the sold homes")
+	}
+	for _, period := range []string{filters.SoldWithin, filters.MaxDaysOnMarket} {
+		if period != "" && !slices.Contains(SEARCH_PERIODS, period) {
+			return fmt.Errorf("Period %s is not one of %s", period, strings.Join(SEARCH_PERIODS, ", "))
+		}
+	}
+	return nil
+}
+
+// UrlSegment gets the filter segment of the search URL, e.g. "/filter/min-price=300k,min-beds=3",
+// which is empty if nothing is filtered
+func (filters Filters) UrlSegment() (string, error) {
+	if err := filters.validate(); err != nil {
+		return "", err
+	}
+
+	var parts []string
+	addPart := func(key string, value string) {
+		parts = append(parts, key+"="+value)
+	}
+	if filters.MinPrice > 0 {
+		addPart("min-price", formatAmount(filters.MinPrice, ""))
+	}
+	if filters.MaxPrice > 0 {
+		addPart("max-price", formatAmount(filters.MaxPrice, ""))
+	}
+	if filters.MinBeds > 0 {
+		addPart("min-beds", strconv.Itoa(filters.MinBeds))
+	}
+	if filters.MaxBeds > 0 {
+		addPart("max-beds", strconv.Itoa(filters.MaxBeds))
+	}
+	if filters.MinBaths > 0 {
+		addPart("min-baths", strconv.FormatFloat(float64(filters.MinBaths), 'f', -1, 32))
+	}
+	if len(filters.PropertyTypes) > 0 {
+		addPart("property-type", strings.Join(filters.PropertyTypes, "+"))
+	}
+	if filters.MinSqft > 0 {
+		addPart("min-sqft", formatAmount(filters.MinSqft, "-sqft"))
+	}
+	if filters.MaxSqft > 0 {
+		addPart("max-sqft", formatAmount(filters.MaxSqft, "-sqft"))
+	}
+	switch filters.Status {
+	case STATUS_SOLD:
+		addPart("include", "sold-"+cmp.Or(filters.SoldWithin, DEFAULT_SOLD_WITHIN))
+	case STATUS_PENDING:
+		addPart("status", "contingent+pending")
+	}
+	if filters.MaxDaysOnMarket != "" {
+		addPart("max-days-on-market", filters.MaxDaysOnMarket)
+	}
+
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return "/filter/" + strings.Join(parts, ","), nil
+}
+
+// formatAmount formats the amount as Redfin does, e.g. "300k" and "1.5M"
+func formatAmount(amount int, suffix string) string {
+	switch {
+	case amount >= 1_000_000:
+		return strconv.FormatFloat(float64(amount)/1_000_000, 'f', -1, 64) + "M" + suffix
+	case amount >= 1_000:
+		return strconv.FormatFloat(float64(amount)/1_000, 'f', -1, 64) + "k" + suffix
+	default:
+		return strconv.Itoa(amount) + suffix
+	}
+}
@@ -0,0 +1,78 @@
+package redfin
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFilters(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Filters
+		wantErr bool
+	}{
+		{"", Filters{}, false},
+		{
+			"min-price=300000, max-price=1500000,min-beds=3,min-baths=2.5,property-type=house+condo",
+			Filters{MinPrice: 300000, MaxPrice: 1500000, MinBeds: 3, MinBaths: 2.5, PropertyTypes: []string{"house", "condo"}},
+			false,
+		},
+		{"status=sold,sold-within=6mo", Filters{Status: STATUS_SOLD, SoldWithin: "6mo"}, false},
+		{"min-price", Filters{}, true},
+		{"min-price=cheap", Filters{}, true},
+		{"min-garages=2", Filters{}, true},
+		{"min-price=500000,max-price=300000", Filters{}, true},
+		{"property-type=castle", Filters{}, true},
+		{"status=rented", Filters{}, true},
+		{"sold-within=6mo", Filters{}, true},
+		{"max-days-on-market=2d", Filters{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			got, err := ParseFilters(test.text)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseFilters(%q) error = %v, want error %t", test.text, err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseFilters(%q) = %+v, want %+v", test.text, got, test.want)
+			}
+		})
+	}
+}
+
+func TestUrlSegment(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters Filters
+		want    string
+		wantErr bool
+	}{
+		{"no filters", Filters{}, "", false},
+		{
+			"all", Filters{
+				MinPrice: 300000, MaxPrice: 1500000, MinBeds: 3, MinBaths: 2.5,
+				PropertyTypes: []string{"house", "condo"}, MinSqft: 1500, Status: STATUS_SOLD, SoldWithin: "6mo",
+			},
+			"/filter/min-price=300k,max-price=1.5M,min-beds=3,min-baths=2.5,property-type=house+condo,min-sqft=1.5k-sqft,include=sold-6mo",
+			false,
+		},
+		{"sold by default", Filters{Status: STATUS_SOLD}, "/filter/include=sold-" + DEFAULT_SOLD_WITHIN, false},
+		{"pending", Filters{Status: STATUS_PENDING}, "/filter/status=contingent+pending", false},
+		{"for sale", Filters{Status: STATUS_FOR_SALE, MaxDaysOnMarket: "1wk"}, "/filter/max-days-on-market=1wk", false},
+		{"small amounts", Filters{MaxPrice: 950, MaxSqft: 999}, "/filter/max-price=950,max-sqft=999-sqft", false},
+		{"invalid", Filters{MinSqft: 1500, MaxSqft: 800}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.filters.UrlSegment()
+			if (err != nil) != test.wantErr {
+				t.Fatalf("UrlSegment() error = %v, want error %t", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("UrlSegment() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package redfin
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+)
+
+// Types of the property history's events
+const (
+	EVENT_LISTED       = "listed"
+	EVENT_PRICE_CHANGE = "price_change"
+	EVENT_PENDING      = "pending"
+	EVENT_SOLD         = "sold"
+	EVENT_DELISTED     = "delisted"
+	EVENT_RENTAL       = "rental"
+	EVENT_OTHER        = "other"
+)
+
+// Keywords of the events' descriptions, e.g. "Price Changed". Rental events like "Rental Listed"
+// are matched first, and delisted before listed, since "delisted" contains "listed".
+var EVENT_KEYWORDS = []struct {
+	event    string
+	keywords []string
+}{
+	{EVENT_RENTAL, []string{"rental", "for rent", "rented"}},
+	{EVENT_SOLD, []string{"sold"}},
+	{EVENT_DELISTED, []string{"delisted", "removed", "withdrawn", "cancelled", "expired"}},
+	{EVENT_PRICE_CHANGE, []string{"price changed", "price change"}},
+	{EVENT_PENDING, []string{"pending", "contingent"}},
+	{EVENT_LISTED, []string{"listed", "relisted", "coming soon"}},
+}
+
+// getStatus gets the status of the listing from its banner, e.g. "SOLD ON MAY 3, 2024".
+// The latest event of the history is used when the banner isn't saved.
+func getStatus(content *goquery.Document, history []object.PriceEvent) string {
+	banner := strings.ToLower(content.Find(".ListingStatusBannerSection").Text())
+	switch {
+	case strings.Contains(banner, "sold"):
+		return STATUS_SOLD
+	case strings.Contains(banner, "pending"), strings.Contains(banner, "contingent"),
+		strings.Contains(banner, "under contract"):
+		return STATUS_PENDING
+	case banner == "" && len(history) > 0:
+		// The history is sorted from the latest event
+		switch history[0].Event {
+		case EVENT_SOLD:
+			return STATUS_SOLD
+		case EVENT_PENDING:
+			return STATUS_PENDING
+		}
+	}
+	return STATUS_FOR_SALE
+}
+
+// getHistory gets the events of the property history table, from the latest one.
+// Homes are allowed to have no history.
+func getHistory(content *goquery.Document) []object.PriceEvent {
+	var history []object.PriceEvent
+	content.Find(".PropertyHistoryEventRow").Each(func(i int, s *goquery.Selection) {
+		description := strings.TrimSpace(s.Find(".description-col div").First().Text())
+		history = append(history, object.PriceEvent{
+			Date:   parseEventDate(s.Find(".col-4 p").First().Text()),
+			Event:  classifyEvent(description),
+			Price:  scraper.StrToFloat32(strings.TrimPrefix(strings.TrimSpace(s.Find(".price-col").Text()), "$")),
+			Source: strings.TrimSpace(s.Find(".description-col .subtext").Text()),
+		})
+	})
+	return history
+}
+
+// classifyEvent gets the type of the event from its description, e.g. "Sold (Public Records)"
+func classifyEvent(description string) string {
+	lowerDescription := strings.ToLower(description)
+	for _, eventKeywords := range EVENT_KEYWORDS {
+		for _, keyword := range eventKeywords.keywords {
+			if strings.Contains(lowerDescription, keyword) {
+				return eventKeywords.event
+			}
+		}
+	}
+	return EVENT_OTHER
+}
+
+// parseEventDate parses the date like "May 3, 2024" to "2024-05-03", it's kept as it is if it's not parsable
+func parseEventDate(text string) string {
+	text = strings.TrimSpace(text)
+	date, err := time.Parse("Jan 2, 2006", text)
+	if err != nil {
+		return text
+	}
+	return date.Format(time.DateOnly)
+}
+
+// ListToSaleRatio gets the ratio of the latest sale's price to the home's last asking price
+// before it, which is false if the history has no listed sale
+func ListToSaleRatio(history []object.PriceEvent) (float32, bool) {
+	for i, event := range history {
+		if event.Event != EVENT_SOLD || event.Price <= 0 {
+			continue
+		}
+		// The older events are after the sale
+		for _, prevEvent := range history[i+1:] {
+			if (prevEvent.Event == EVENT_LISTED || prevEvent.Event == EVENT_PRICE_CHANGE) && prevEvent.Price > 0 {
+				return event.Price / prevEvent.Price, true
+			}
+		}
+		return 0, false
+	}
+	return 0, false
+}
@@ -0,0 +1,67 @@
+package redfin
+
+import (
+	"testing"
+
+	"github.com/mikehquan19/useful-scraper/object"
+)
+
+func TestClassifyEvent(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"Listed (Active)", EVENT_LISTED},
+		{"Relisted", EVENT_LISTED},
+		{"Coming Soon", EVENT_LISTED},
+		{"Price Changed", EVENT_PRICE_CHANGE},
+		{"Pending", EVENT_PENDING},
+		{"Contingent", EVENT_PENDING},
+		{"Sold (Public Records)", EVENT_SOLD},
+		{"Delisted", EVENT_DELISTED},
+		{"Listing Removed", EVENT_DELISTED},
+		{"Listing Withdrawn", EVENT_DELISTED},
+		{"Rental Listed", EVENT_RENTAL},
+		{"Listed for Rent", EVENT_RENTAL},
+		{"Rental Removed", EVENT_RENTAL},
+		{"Tax Assessment", EVENT_OTHER},
+		{"", EVENT_OTHER},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if got := classifyEvent(test.description); got != test.want {
+				t.Errorf("classifyEvent(%q) = %s, want %s", test.description, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseEventDate(t *testing.T) {
+	if got := parseEventDate(" May 3, 2024 "); got != "2024-05-03" {
+		t.Errorf("parseEventDate() = %s, want 2024-05-03", got)
+	}
+	if got := parseEventDate("Today"); got != "Today" {
+		t.Errorf("parseEventDate() = %s, want the text kept", got)
+	}
+}
+
+func TestListToSaleRatio(t *testing.T) {
+	history := []object.PriceEvent{
+		{Event: EVENT_SOLD, Price: 440000},
+		{Event: EVENT_PENDING},
+		{Event: EVENT_PRICE_CHANGE, Price: 460000},
+		{Event: EVENT_LISTED, Price: 480000},
+	}
+	ratio, ok := ListToSaleRatio(history)
+	if !ok || ratio != 440000.0/460000.0 {
+		t.Errorf("ListToSaleRatio() = %v, %t, want %v, true", ratio, ok, 440000.0/460000.0)
+	}
+
+	if _, ok := ListToSaleRatio(history[1:]); ok {
+		t.Error("ListToSaleRatio() of the history without a sale is ok")
+	}
+	if _, ok := ListToSaleRatio(history[:2]); ok {
+		t.Error("ListToSaleRatio() of the sale without a listed price is ok")
+	}
+}
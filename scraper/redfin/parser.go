@@ -0,0 +1,342 @@
+package redfin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper"
+	"github.com/mikehquan19/useful-scraper/scraper/geocode"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ParseHouse parses the housing info of the HTML files saved under the directory and geocodes them.
+// A *scraper.PartialError is returned with the houses when some of the files are skipped.
+func (src *Source) ParseHouse(ctx context.Context, dirName string) ([]*object.HomeInfo, error) {
+	var homeInfos []*object.HomeInfo
+	fmt.Println("Parsing home infos...")
+
+	parseErrs, err := scraper.ParseHTMLFiles(ctx, dirName, func(path string, htmlContent *goquery.Document) error {
+		homeInfo, err := ParseHomeHTML(path, htmlContent)
+		if err != nil {
+			return err
+		}
+		homeInfos = append(homeInfos, homeInfo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Geocode the parsed houses whose pages have no coordinates
+	var missingInfos []*object.HomeInfo
+	for _, homeInfo := range homeInfos {
+		if homeInfo.Lat == 0 && homeInfo.Lon == 0 {
+			missingInfos = append(missingInfos, homeInfo)
+		} else {
+			homeInfo.Geo = object.GeoMatch{Source: GEO_SOURCE_PAGE, Status: geocode.STATUS_MATCHED}
+		}
+	}
+	if len(missingInfos) > 0 {
+		if err = src.geocode(ctx, missingInfos); ctx.Err() != nil {
+			return nil, ctx.Err()
+		} else if err != nil {
+			// The houses are still parsed, the ones which aren't geocoded are kept without coordinates
+			fmt.Printf("Failed to geocode some of the %d home infos, they're kept without coordinates\n%s\n", len(missingInfos), err)
+		}
+	}
+
+	fmt.Printf("Parsed %d home infos completely!\n", len(homeInfos))
+	return homeInfos, scraper.NewPartialError(len(homeInfos), parseErrs)
+}
+
+// ParseHomeHTML parses the housing info of the house's saved HTML, the url is
+// the page's link or the path of the file. It's not geocoded yet. The structured
+// data saved by EXTRACT_DATA is parsed instead of the sections when it's there.
+func ParseHomeHTML(url string, htmlContent *goquery.Document) (*object.HomeInfo, error) {
+	if htmlContent.Find(".redfin-data").Length() > 0 {
+		return parseHomeData(url, htmlContent)
+	}
+	parseErr := func(field string, err error) error {
+		return &scraper.ParseError{Url: url, Field: field, Err: err}
+	}
+
+	// Parse the value from the HTML doc
+	address, err := getAddress(htmlContent)
+	if err != nil {
+		return nil, parseErr("address", err)
+	}
+	bedrooms, bathrooms, err := getRooms(htmlContent)
+	if err != nil {
+		return nil, parseErr("rooms", err)
+	}
+	area, err := getArea(htmlContent)
+	if err != nil {
+		return nil, parseErr("area", err)
+	}
+	price, err := getPrice(htmlContent)
+	if err != nil {
+		return nil, parseErr("price", err)
+	}
+	schools, err := getSchools(htmlContent)
+	if err != nil {
+		return nil, parseErr("schools", err)
+	}
+
+	// Details are allowed to be missing
+	detailsMap := getDetails(url, htmlContent)
+	propertyType, _ := detailsMap["Property Type"].(string)
+	yearBuilt, _ := detailsMap["Year Built"].(string)
+	history := getHistory(htmlContent)
+	lat, lon := getCoordinates(htmlContent)
+	return &object.HomeInfo{
+		Id:           primitive.NewObjectID(),
+		Address:      address,
+		Lat:          lat,
+		Lon:          lon,
+		Description:  htmlContent.Find(".remarks").Text(),
+		Bedrooms:     bedrooms,
+		Bathrooms:    bathrooms,
+		HomeArea:     area,
+		Price:        price,
+		PropertyType: propertyType,
+		YearBuilt:    yearBuilt,
+		PricePerUnit: detailsMap["Price/Sq.Ft."].(float32),
+		LotArea:      detailsMap["Lot Size"].(object.Area),
+		HOADues:      detailsMap["HOA Dues"].(float32),
+		Parking:      detailsMap["Parking"].(string),
+		Schools:      schools,
+		Status:       getStatus(htmlContent, history),
+		History:      history,
+	}, nil
+}
+
+func getAddress(content *goquery.Document) (object.Address, error) {
+	addrParts := strings.Split(content.Find(".full-address").Text(), ", ")
+	if len(addrParts) < 3 {
+		return object.Address{}, fmt.Errorf("Address missing info, which is non-parsable")
+	}
+	stateAndZip := strings.Split(addrParts[2], " ")
+	if len(stateAndZip) < 2 {
+		return object.Address{}, fmt.Errorf("Address missing zip code, which is non-parsable")
+	}
+
+	return object.Address{
+		Street:  addrParts[0],
+		City:    addrParts[1],
+		State:   stateAndZip[0],
+		Zipcode: stateAndZip[1],
+	}, nil
+}
+
+func getRooms(content *goquery.Document) (float32, float32, error) {
+	text := content.Find(".beds-section .statsValue").Text()
+	bedrooms, err := strconv.ParseFloat(text, 32)
+	if err != nil {
+		// Houses must have bedrooms and bathrooms
+		return 0, 0, err
+	}
+	text = content.Find(".baths-section .bath-flyout").Text()
+	// Extract num because it is displayed together with labels
+	bathrooms, err := strconv.ParseFloat(strings.Split(text, " ")[0], 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return float32(bedrooms), float32(bathrooms), nil
+}
+
+func getArea(content *goquery.Document) (object.Area, error) {
+	// Remove any space among the unit
+	text := content.Find(".sqft-section .statsLabel").Text()
+	unit := strings.ReplaceAll(content.Find(".sqft-section .statsLabel").Text(), " ", "")
+
+	text = content.Find(".sqft-section .statsValue").Text()
+	// Remove the "," from the number to parse
+	value, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), 32)
+	if err != nil {
+		// This house's listing has invalid area
+		return object.Area{}, err
+	}
+
+	return object.Area{
+		Unit:  unit,
+		Value: float32(value),
+	}, nil
+}
+
+func getPrice(content *goquery.Document) (float32, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(content.Find(".price").Text()), "$")
+	price, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), 32)
+	if err != nil {
+		// This house's listing has invalid price
+		return 0, err
+	}
+
+	return float32(price), nil
+}
+
+// getDetails gets the key details of the house, which are allowed to be missing.
+// The details which are there but malformed are printed and left at 0.
+func getDetails(url string, content *goquery.Document) map[string]any {
+	detailsMap := make(map[string]any)
+	content.Find(".keyDetails-value").Each(func(i int, s *goquery.Selection) {
+		detailsMap[s.Find(".valueType").Text()] = s.Find(".valueText").Text()
+	})
+
+	// NOTE: Home is allowed to have missing or invalid details, we will ignore it
+	// Parse and cast type of the lot size
+	_, ok := detailsMap["Lot Size"]
+	if !ok {
+		detailsMap["Lot Size"] = object.Area{}
+	} else {
+		lotSizeParts := strings.Fields(detailsMap["Lot Size"].(string))
+		var value float64
+		err := fmt.Errorf("Lot size %q has no unit", detailsMap["Lot Size"])
+		if len(lotSizeParts) >= 2 {
+			value, err = strconv.ParseFloat(strings.ReplaceAll(lotSizeParts[0], ",", ""), 32)
+		}
+		if err != nil {
+			fmt.Printf("Skipped the lot size of %s\n%s\n", url, err)
+			detailsMap["Lot Size"] = object.Area{}
+		} else {
+			// "sqft" is separated as "sq ft" in HTML but "acres" is not
+			unit := lotSizeParts[1]
+			if len(lotSizeParts) > 2 {
+				unit += lotSizeParts[2]
+			}
+			detailsMap["Lot Size"] = object.Area{
+				Unit:  unit,
+				Value: float32(value),
+			}
+		}
+	}
+
+	// Parse the price per unit
+	_, ok = detailsMap["Price/Sq.Ft."]
+	if !ok {
+		detailsMap["Price/Sq.Ft."] = float32(0)
+	} else {
+		text := strings.TrimPrefix(strings.TrimSpace(detailsMap["Price/Sq.Ft."].(string)), "$")
+		value, err := strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), 32)
+		if err != nil {
+			fmt.Printf("Skipped the price per sqft of %s\n%s\n", url, err)
+			detailsMap["Price/Sq.Ft."] = float32(0.0)
+		} else {
+			detailsMap["Price/Sq.Ft."] = float32(value)
+		}
+	}
+
+	// Parse the HOA
+	_, ok = detailsMap["HOA Dues"]
+	if !ok {
+		detailsMap["HOA Dues"] = float32(0)
+	} else {
+		// Extract the float32 number from it
+		text := scraper.NUMBER_REGEX.FindString(detailsMap["HOA Dues"].(string))
+		value, err := strconv.ParseFloat(text, 32)
+		if err != nil {
+			detailsMap["HOA Dues"] = float32(0)
+		} else {
+			detailsMap["HOA Dues"] = float32(value)
+		}
+	}
+
+	// Parse the parking
+	_, ok = detailsMap["Parking"]
+	if !ok {
+		detailsMap["Parking"] = ""
+	}
+
+	return detailsMap
+}
+
+func getSchools(content *goquery.Document) ([]object.School, error) {
+	var nearbySchools []object.School
+	var err error
+
+	content.Find(".ListItem__content").Each(func(i int, s *goquery.Selection) {
+		schoolDescription := strings.Split(s.Find(".ListItem__description").Text(), " • ")
+		if len(schoolDescription) < 3 {
+			err = fmt.Errorf("Description missing information")
+			return
+		}
+		nearbySchools = append(nearbySchools, object.School{
+			Name:     s.Find(".ListItem__heading").Text(),
+			Type:     schoolDescription[0],
+			Distance: schoolDescription[2],
+		})
+	})
+
+	return nearbySchools, err
+}
+
+// Source of the coordinates captured from the house's page
+const GEO_SOURCE_PAGE = "page"
+
+// getCoordinates gets the coordinates captured from the house's page, which are 0 if there are none
+func getCoordinates(content *goquery.Document) (float32, float32) {
+	geoInfo := content.Find(".home-geo")
+	lat, latErr := strconv.ParseFloat(geoInfo.AttrOr("data-lat", ""), 32)
+	lon, lonErr := strconv.ParseFloat(geoInfo.AttrOr("data-lon", ""), 32)
+	if latErr != nil || lonErr != nil {
+		return 0, 0
+	}
+	return float32(lat), float32(lon)
+}
+
+// geocode gets the coordinates of the houses with the geocoder of the options
+func (src *Source) geocode(ctx context.Context, homeInfos []*object.HomeInfo) error {
+	geocoder := src.opts.Geocoder
+	if geocoder == nil {
+		geocoder = geocode.NewMapbox(os.Getenv("MAPBOX_ACCESS_TOKEN"))
+	}
+	fmt.Printf("Geocoding %d home infos without coordinates with %s...\n", len(homeInfos), geocoder.Name())
+
+	var addresses []object.Address
+	for _, homeInfo := range homeInfos {
+		addresses = append(addresses, homeInfo.Address)
+	}
+	// The results geocoded before an error are still applied
+	results, err := geocoder.Geocode(ctx, addresses)
+	if len(results) != len(homeInfos) {
+		if err == nil {
+			err = fmt.Errorf("%s returned %d results for %d addresses", geocoder.Name(), len(results), len(homeInfos))
+		}
+		results = make([]geocode.Result, len(homeInfos))
+	}
+
+	// The unmatched houses are kept without coordinates, flagged by their status
+	unmatched, partial := 0, 0
+	for i, homeInfo := range homeInfos {
+		homeInfo.Geo = object.GeoMatch{
+			Source:    geocoder.Name(),
+			Status:    results[i].Status,
+			Accuracy:  results[i].Accuracy,
+			Relevance: results[i].Relevance,
+		}
+		switch {
+		case results[i].Status == "":
+			// The address isn't geocoded because of the error
+			homeInfo.Geo.Status = geocode.STATUS_FAILED
+			unmatched += 1
+			continue
+		case !results[i].Matched():
+			// Coordinates out of the unmatched results aren't trusted
+			unmatched += 1
+			continue
+		case results[i].Status == geocode.STATUS_PARTIAL:
+			partial += 1
+		}
+		homeInfo.Lat = results[i].Lat
+		homeInfo.Lon = results[i].Lon
+	}
+	if unmatched > 0 || partial > 0 {
+		fmt.Printf("%d home infos aren't matched and %d are only partially matched by %s\n", unmatched, partial, geocoder.Name())
+	}
+	return err
+}
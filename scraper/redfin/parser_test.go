@@ -0,0 +1,116 @@
+package redfin
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikehquan19/useful-scraper/object"
+	"github.com/mikehquan19/useful-scraper/scraper/geocode"
+)
+
+// stubGeocoder returns its results and its error for any addresses
+type stubGeocoder struct {
+	results []geocode.Result
+	err     error
+}
+
+func (geocoder *stubGeocoder) Name() string {
+	return "stub"
+}
+
+func (geocoder *stubGeocoder) Geocode(ctx context.Context, addresses []object.Address) ([]geocode.Result, error) {
+	return geocoder.results, geocoder.err
+}
+
+func TestGeocodePartialResults(t *testing.T) {
+	errBatch := errors.New("batch failed")
+	tests := []struct {
+		name       string
+		geocoder   *stubGeocoder
+		wantStatus []string
+		wantLat    []float32
+	}{
+		{
+			"results before the error",
+			&stubGeocoder{
+				results: []geocode.Result{
+					{Lat: 33, Lon: -96, Status: geocode.STATUS_MATCHED, Accuracy: geocode.ACCURACY_ROOFTOP},
+					{Status: geocode.STATUS_UNMATCHED},
+					{},
+				},
+				err: errBatch,
+			},
+			[]string{geocode.STATUS_MATCHED, geocode.STATUS_UNMATCHED, geocode.STATUS_FAILED},
+			[]float32{33, 0, 0},
+		},
+		{
+			"no results",
+			&stubGeocoder{err: errBatch},
+			[]string{geocode.STATUS_FAILED, geocode.STATUS_FAILED, geocode.STATUS_FAILED},
+			[]float32{0, 0, 0},
+		},
+		{
+			"too few results",
+			&stubGeocoder{results: []geocode.Result{{Lat: 33, Lon: -96, Status: geocode.STATUS_MATCHED}}},
+			[]string{geocode.STATUS_FAILED, geocode.STATUS_FAILED, geocode.STATUS_FAILED},
+			[]float32{0, 0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			homeInfos := []*object.HomeInfo{{}, {}, {}}
+			err := New(Options{Geocoder: test.geocoder}).geocode(context.Background(), homeInfos)
+			if err == nil {
+				t.Error("geocode() error = nil, want the geocoder's error")
+			}
+			for i, homeInfo := range homeInfos {
+				if homeInfo.Geo.Status != test.wantStatus[i] || homeInfo.Lat != test.wantLat[i] {
+					t.Errorf("Home %d has status %q and lat %v, want %q and %v",
+						i, homeInfo.Geo.Status, homeInfo.Lat, test.wantStatus[i], test.wantLat[i])
+				}
+				if homeInfo.Geo.Source != "stub" {
+					t.Errorf("Home %d has source %q, want stub", i, homeInfo.Geo.Source)
+				}
+			}
+		})
+	}
+}
+
+func TestGetDetails(t *testing.T) {
+	tests := []struct {
+		name         string
+		lotSize      string
+		pricePerSqft string
+		wantLotSize  object.Area
+		wantPrice    float32
+	}{
+		{"valid", "0.25 Acres", "$1,234", object.Area{Unit: "Acres", Value: 0.25}, 1234},
+		{"sqft", "7,405 sq ft", "$250", object.Area{Unit: "sqft", Value: 7405}, 250},
+		{"no unit", "—", "", object.Area{}, 0},
+		{"not numbers", "— Acres", "$—", object.Area{}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := goquery.NewDocumentFromReader(strings.NewReader(
+				`<div class="keyDetails-value"><span class="valueType">Lot Size</span><span class="valueText">` +
+					test.lotSize + `</span></div><div class="keyDetails-value"><span class="valueType">Price/Sq.Ft.</span>` +
+					`<span class="valueText">` + test.pricePerSqft + `</span></div>`,
+			))
+			if err != nil {
+				t.Fatal(err)
+			}
+			detailsMap := getDetails("test.html", content)
+			if got := detailsMap["Lot Size"].(object.Area); got != test.wantLotSize {
+				t.Errorf("Lot size = %+v, want %+v", got, test.wantLotSize)
+			}
+			if got := detailsMap["Price/Sq.Ft."].(float32); got != test.wantPrice {
+				t.Errorf("Price per sqft = %v, want %v", got, test.wantPrice)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package redfin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+	"github.com/mikehquan19/useful-scraper/scraper"
+)
+
+// File the resolved regions of the CLI are cached in
+const DEFAULT_REGION_CACHE = "./data/redfin_regions.json"
+
+const AUTOCOMPLETE_URL = REDFIN_URL + "/stingray/do/location-autocomplete"
+
+// Kinds of the Redfin regions whose pages list the homes for sale
+var REGION_PREFIXES = []string{"/city/", "/zipcode/", "/neighborhood/", "/county/"}
+
+// Row of the location autocomplete, e.g. "Plano, TX, USA" at /city/30868/TX/Plano
+type autocompleteRow struct {
+	Name    string `json:"name"`
+	SubName string `json:"subName"`
+	Url     string `json:"url"`
+}
+
+type autocompleteResponse struct {
+	ErrorMessage string `json:"errorMessage"`
+	Payload      struct {
+		ExactMatch *autocompleteRow `json:"exactMatch"`
+		Sections   []struct {
+			Rows []autocompleteRow `json:"rows"`
+		} `json:"sections"`
+	} `json:"payload"`
+}
+
+// ResolveRegion gets the Redfin path of the city, e.g. "Plano, TX", or the ZIP code.
+// The city hrefs of the options are looked up first, then the regions cached on disk,
+// then the region is looked up with Redfin's location autocomplete and cached.
+func (src *Source) ResolveRegion(cdpCtx context.Context, city string) (string, error) {
+	key := strings.ToLower(strings.TrimSpace(city))
+	if cityHref, exists := src.opts.CityHrefs[key]; exists {
+		return cityHref, nil
+	}
+
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	if err := src.loadRegions(); err != nil {
+		return "", err
+	}
+	if regionHref, exists := src.regions[key]; exists {
+		return regionHref, nil
+	}
+
+	regionHref, err := lookupRegion(cdpCtx, city)
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Resolved %s to %s\n", city, regionHref)
+	src.regions[key] = regionHref
+	if err = src.saveRegions(); err != nil {
+		// The region is still resolved, it's only looked up again next time
+		fmt.Printf("Failed to cache the region of %s\n%s\n", city, err)
+	}
+	return regionHref, nil
+}
+
+// lookupRegion looks up the region of the city or ZIP code with Redfin's location autocomplete
+func lookupRegion(cdpCtx context.Context, city string) (string, error) {
+	values := url.Values{}
+	values.Set("location", city)
+	values.Set("v", "2")
+
+	var body string
+	_, err := scraper.Navigate(cdpCtx, AUTOCOMPLETE_URL+"?"+values.Encode(),
+		chromedp.Evaluate(`document.body.innerText`, &body),
+	)
+	if err != nil {
+		return "", fmt.Errorf("Failed to look up the region of %s\n%w", city, err)
+	}
+
+	// The JSON is prefixed with "{}&&" against JSON hijacking
+	var response autocompleteResponse
+	if err = json.Unmarshal([]byte(strings.TrimPrefix(body, "{}&&")), &response); err != nil {
+		return "", fmt.Errorf("Failed to read the region of %s\n%w", city, err)
+	}
+
+	rows := []autocompleteRow{}
+	if response.Payload.ExactMatch != nil {
+		rows = append(rows, *response.Payload.ExactMatch)
+	}
+	for _, section := range response.Payload.Sections {
+		rows = append(rows, section.Rows...)
+	}
+	for _, row := range rows {
+		for _, prefix := range REGION_PREFIXES {
+			if strings.HasPrefix(row.Url, prefix) {
+				return row.Url, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("The city %s either doesn't exist or is not supported by Redfin", city)
+}
+
+// loadRegions loads the cached regions once, the lock must be held
+func (src *Source) loadRegions() error {
+	if src.regions != nil {
+		return nil
+	}
+	src.regions = make(map[string]string)
+	if src.opts.RegionCache == "" {
+		return nil
+	}
+
+	jsonData, err := os.ReadFile(src.opts.RegionCache)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("Failed to read region cache %s\n%w", src.opts.RegionCache, err)
+	}
+	if err = json.Unmarshal(jsonData, &src.regions); err != nil {
+		return fmt.Errorf("Failed to read region cache %s\n%w", src.opts.RegionCache, err)
+	}
+	return nil
+}
+
+// saveRegions writes the regions to the cache, the lock must be held
+func (src *Source) saveRegions() error {
+	if src.opts.RegionCache == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(src.opts.RegionCache), 0755); err != nil {
+		return err
+	}
+	jsonData, err := json.MarshalIndent(src.regions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return scraper.WriteFileAtomic(src.opts.RegionCache, jsonData)
+}
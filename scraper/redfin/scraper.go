@@ -0,0 +1,246 @@
+/* Housing data scraper from Redfin.com */
+
+package redfin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/mikehquan19/useful-scraper/scraper"
+	"github.com/mikehquan19/useful-scraper/scraper/geocode"
+)
+
+const REDFIN_URL string = "https://www.redfin.com"
+
+// Coordinates of the home from the page's metadata, which are the JSON-LD's geo,
+// or the meta tags of the location. They're null when the page has none.
+const COORDINATES_SCRIPT string = `(() => {
+	const findGeo = (node) => {
+		if (!node || typeof node !== "object") return null;
+		if (node.geo && node.geo.latitude && node.geo.longitude) return node.geo;
+		for (const child of Object.values(node)) {
+			const geo = findGeo(child);
+			if (geo) return geo;
+		}
+		return null;
+	};
+	for (const script of document.querySelectorAll('script[type="application/ld+json"]')) {
+		try {
+			const geo = findGeo(JSON.parse(script.textContent));
+			if (geo) return {lat: Number(geo.latitude), lon: Number(geo.longitude)};
+		} catch (e) {}
+	}
+	const meta = (name) => document.querySelector('meta[property="' + name + '"], meta[name="' + name + '"]')?.content;
+	if (meta("place:location:latitude") && meta("place:location:longitude")) {
+		return {lat: Number(meta("place:location:latitude")), lon: Number(meta("place:location:longitude"))};
+	}
+	const position = (meta("geo.position") ?? meta("ICBM") ?? "").split(/[;,]/);
+	if (position.length === 2 && position[0].trim() && position[1].trim()) {
+		return {lat: Number(position[0]), lon: Number(position[1])};
+	}
+	return null;
+})()`
+
+// Coordinates of the home captured from its page
+type Coordinates struct {
+	Lat float32 `json:"lat"`
+	Lon float32 `json:"lon"`
+}
+
+func init() {
+	scraper.RegisterSource(New(Options{RegionCache: DEFAULT_REGION_CACHE}))
+}
+
+// Options of the Redfin source
+type Options struct {
+	// Redfin paths of the cities by name, DEFAULT_CITY_HREFS if nil.
+	// The other cities and ZIP codes are looked up on Redfin.
+	CityHrefs map[string]string
+	// JSON file the looked up regions are cached in, they're only cached in memory if empty
+	RegionCache string
+	// Filters of the search, the homes for sale aren't filtered if it's empty
+	Filters Filters
+	// Extraction mode of the homes' pages, EXTRACT_HTML if empty
+	Extract string
+	// Geocoder of the parsed houses without coordinates, Mapbox with MAPBOX_ACCESS_TOKEN
+	// of the environment if nil. It's wrapped with geocode.NewCache to cache its results.
+	Geocoder geocode.Geocoder
+}
+
+// Source of the houses for sale on Redfin
+type Source struct {
+	opts Options
+
+	mu sync.Mutex
+	// Redfin paths of the looked up cities and ZIP codes, loaded from the cache
+	regions map[string]string
+}
+
+// New creates the Redfin source with the options
+func New(opts Options) *Source {
+	if opts.CityHrefs == nil {
+		opts.CityHrefs = DEFAULT_CITY_HREFS
+	}
+	if opts.Extract == "" {
+		opts.Extract = EXTRACT_HTML
+	}
+	return &Source{opts: opts}
+}
+
+func (src *Source) Name() string {
+	return "redfin"
+}
+
+func (src *Source) ObjectType() string {
+	return "house"
+}
+
+func (src *Source) ListLinks(cdpCtx context.Context, query scraper.Query) ([]string, error) {
+	return src.GetHomeLinks(cdpCtx, query.City)
+}
+
+func (src *Source) DetailName(link string) string {
+	return path.Base(strings.TrimRight(link, "/"))
+}
+
+func (src *Source) FetchDetail(cdpCtx context.Context, link string) ([]byte, error) {
+	if src.opts.Extract == EXTRACT_DATA {
+		return GetHomeData(cdpCtx, link)
+	}
+	return GetHomeHTML(cdpCtx, link)
+}
+
+func (src *Source) Parse(ctx context.Context, dirName string) (any, error) {
+	return src.ParseHouse(ctx, dirName)
+}
+
+// Settings gets the filters of the search, which is nil if nothing is filtered
+func (src *Source) Settings() any {
+	if segment, err := src.opts.Filters.UrlSegment(); err != nil || segment == "" {
+		return nil
+	}
+	return src.opts.Filters
+}
+
+// GetHomeLinks gets the list of links to the each home of the city, e.g. "Plano, TX", or the ZIP code
+func (src *Source) GetHomeLinks(cdpCtx context.Context, city string) ([]string, error) {
+	cityHref, err := src.ResolveRegion(cdpCtx, city)
+	if err != nil {
+		return nil, err
+	}
+	filterSegment, err := src.opts.Filters.UrlSegment()
+	if err != nil {
+		return nil, err
+	}
+	// The filters go after the region, e.g. /city/30868/TX/Plano/filter/min-beds=3
+	cityHref += filterSegment
+
+	var homeLinks []string
+	fmt.Println("Scraping home links...")
+
+	// Get the all the page links, the small regions have only one page without the page numbers
+	var pageNodes []*cdp.Node
+	_, err = scraper.Navigate(cdpCtx, REDFIN_URL+cityHref,
+		chromedp.WaitVisible(".bp-Homecard__Address"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	hasPages, err := scraper.ElementExists(cdpCtx, ".PageNumbers__page")
+	if err != nil {
+		return nil, err
+	}
+	pageHrefs := []string{cityHref}
+	if hasPages {
+		err = chromedp.Run(cdpCtx, chromedp.Nodes(".PageNumbers__page", &pageNodes, chromedp.ByQueryAll))
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get the pages of %s\n%w", city, err)
+		}
+		pageHrefs = nil
+		for _, pageNode := range pageNodes {
+			pageHref, hrefExists := pageNode.Attribute("href")
+			if !hrefExists {
+				return nil, fmt.Errorf("Can't get links page")
+			}
+			pageHrefs = append(pageHrefs, pageHref)
+		}
+	}
+
+	// Navigate to each page and get all the home links
+	for _, pageHref := range pageHrefs {
+		var homeNodes []*cdp.Node
+		_, err = scraper.Navigate(cdpCtx, REDFIN_URL+pageHref,
+			chromedp.WaitVisible(".bp-Homecard__Address"),
+			chromedp.Nodes(".bp-Homecard__Address", &homeNodes, chromedp.ByQueryAll),
+		)
+		if err != nil {
+			// Skip this page, the homes of the other pages are still scraped
+			fmt.Printf("Failed to scrape home links of %s\n%s\n", pageHref, err)
+			scraper.RecordFailure(cdpCtx, REDFIN_URL+pageHref, err)
+			continue
+		}
+
+		for _, homeNode := range homeNodes {
+			homeHref, exists := homeNode.Attribute("href")
+			if !exists {
+				return nil, errors.New("can't get homelinks")
+			}
+			homeLinks = append(homeLinks, REDFIN_URL+homeHref)
+		}
+	}
+
+	fmt.Printf("Sucessfully scaped %d home links!\n", len(homeLinks))
+	return homeLinks, nil
+}
+
+// GetHomeHTML navigates to the house's page and gets the HTML of its relevant sections,
+// with the coordinates of the page's metadata so the house doesn't have to be geocoded
+func GetHomeHTML(cdpCtx context.Context, homeLink string) ([]byte, error) {
+	var basicInfo, keyDetails, description, schoolInfo, agentInfo, statusBanner, history string
+	var coordinates *Coordinates
+
+	_, err := scraper.Navigate(cdpCtx, homeLink,
+		chromedp.WaitVisible(".AddressBannerV2"),
+		chromedp.OuterHTML(".AddressBannerV2", &basicInfo, chromedp.ByQuery),
+
+		chromedp.WaitVisible(".keyDetailsList"),
+		chromedp.OuterHTML(".keyDetailsList", &keyDetails, chromedp.ByQuery),
+		chromedp.Evaluate(COORDINATES_SCRIPT, &coordinates),
+	)
+	if err != nil {
+		return nil, err
+	}
+	geoInfo := ""
+	if coordinates != nil {
+		geoInfo = fmt.Sprintf(`<div class="home-geo" data-lat="%f" data-lon="%f"></div>`, coordinates.Lat, coordinates.Lon)
+	}
+
+	err = scraper.ExtractOrSkip(cdpCtx, ".sectionContent .remarks", "description", &description)
+	if err != nil {
+		return nil, err
+	}
+	err = scraper.ExtractOrSkip(cdpCtx, ".schools-content", "schools", &schoolInfo)
+	if err != nil {
+		return nil, err
+	}
+	// The banner shows if the home is pending or sold
+	err = scraper.ExtractOrSkip(cdpCtx, ".ListingStatusBannerSection", "status banner", &statusBanner)
+	if err != nil {
+		return nil, err
+	}
+	err = scraper.ExtractOrSkip(cdpCtx, ".PropertyHistory", "property history", &history)
+	if err != nil {
+		return nil, err
+	}
+
+	return fmt.Appendf(nil,
+		"<div>%s%s%s%s%s%s%s%s</div>",
+		geoInfo, statusBanner, basicInfo, keyDetails, description, agentInfo, schoolInfo, history,
+	), nil
+}
@@ -0,0 +1,169 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var DEFAULT_RETRY_POLICY = RetryPolicy{
+	MaxAttempts:    3,
+	BaseDelay:      2 * time.Second,
+	MaxDelay:       30 * time.Second,
+	AttemptTimeout: 45 * time.Second,
+}
+
+// RetryPolicy of the navigations and the selector waits, the delay is doubled after each attempt
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Timeout of each attempt, so a hanging selector wait is retried too
+	AttemptTimeout time.Duration
+}
+
+// withDefaults gets the policy with the fields of DEFAULT_RETRY_POLICY where they aren't set,
+// e.g. the policy which only sets the attempts still has the attempt timeout
+func (policy RetryPolicy) withDefaults() RetryPolicy {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DEFAULT_RETRY_POLICY.MaxAttempts
+	}
+	if policy.BaseDelay == 0 {
+		policy.BaseDelay = DEFAULT_RETRY_POLICY.BaseDelay
+	}
+	if policy.MaxDelay == 0 {
+		policy.MaxDelay = DEFAULT_RETRY_POLICY.MaxDelay
+	}
+	if policy.AttemptTimeout <= 0 {
+		policy.AttemptTimeout = DEFAULT_RETRY_POLICY.AttemptTimeout
+	}
+	return policy
+}
+
+// Error of the non-OK response status of a navigation
+type StatusError struct {
+	Url    string
+	Status int64
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("%s responded with status %d", err.Url, err.Status)
+}
+
+// isRetryable checks if the error is transient, which are timeouts, network errors,
+// blocks after backing off, 5xx responses and 429 responses
+func isRetryable(err error) bool {
+	if errors.Is(err, ErrBlocked) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Status >= 500 || statusErr.Status == 429
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	// Chrome's network errors, e.g. "net::ERR_CONNECTION_RESET"
+	return strings.Contains(err.Error(), "net::ERR_")
+}
+
+// withRetry runs the attempt until it succeeds, the error isn't retryable,
+// or the attempts are exhausted. The wait before each attempt, e.g. for the rate
+// limiter, isn't counted towards the attempt's timeout, it can be nil.
+func withRetry(ctx context.Context, desc string, wait func(ctx context.Context) error, attempt func(ctx context.Context) error) error {
+	retryPolicy := getSession(ctx).retryPolicy
+	// The negative delays are clamped, the jitter can't be drawn from them
+	delay := max(retryPolicy.BaseDelay, 0)
+	for attemptNum := 1; ; attemptNum++ {
+		if wait != nil {
+			if err := wait(ctx); err != nil {
+				return err
+			}
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, retryPolicy.AttemptTimeout)
+		err := attempt(attemptCtx)
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			// The whole run is stopped, not only this attempt
+			return ctx.Err()
+		}
+		if !isRetryable(err) {
+			return err
+		}
+		if attemptNum >= retryPolicy.MaxAttempts {
+			return fmt.Errorf("%s failed after %d attempts\n%w", desc, attemptNum, err)
+		}
+
+		// Add the jitter so the tabs don't retry at the same time
+		retryDelay := delay + rand.N(delay/2+1)
+		fmt.Printf("%s failed, retrying in %s\n%s\n", desc, retryDelay.Round(time.Millisecond), err)
+		timer := time.NewTimer(retryDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		delay = max(min(delay*2, retryPolicy.MaxDelay), 0)
+	}
+}
+
+// Failure is a link that failed to be scraped after all of the attempts
+type Failure struct {
+	Link  string `json:"link"`
+	Error string `json:"error"`
+	Err   error  `json:"-"`
+}
+
+// failureList is the failures of the run
+type failureList struct {
+	mu   sync.Mutex
+	list []Failure
+}
+
+// RecordFailure records the failed link of the run, so the run can go on without it.
+// The links stopped by the interrupt aren't failed, they're left for the next run.
+func RecordFailure(ctx context.Context, link string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	failures := getSession(ctx).failures
+	failures.mu.Lock()
+	defer failures.mu.Unlock()
+	failures.list = append(failures.list, Failure{Link: link, Error: err.Error(), Err: err})
+}
+
+// all gets a copy of the recorded failures
+func (failures *failureList) all() []Failure {
+	failures.mu.Lock()
+	defer failures.mu.Unlock()
+	return append([]Failure(nil), failures.list...)
+}
+
+// writeFailures writes the failures to file, if there's any
+func writeFailures(failureList []Failure, outDir string) error {
+	if len(failureList) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outDir), 0755); err != nil {
+		return err
+	}
+	jsonData, err := json.MarshalIndent(failureList, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d links failed, see %s\n", len(failureList), outDir)
+	return WriteFileAtomic(outDir, jsonData)
+}
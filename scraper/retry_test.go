@@ -0,0 +1,36 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		want   RetryPolicy
+	}{
+		{"empty", RetryPolicy{}, DEFAULT_RETRY_POLICY},
+		{
+			"only attempts", RetryPolicy{MaxAttempts: 5},
+			RetryPolicy{MaxAttempts: 5, BaseDelay: 2 * time.Second, MaxDelay: 30 * time.Second, AttemptTimeout: 45 * time.Second},
+		},
+		{
+			"negative timeout", RetryPolicy{BaseDelay: time.Second, AttemptTimeout: -time.Second},
+			RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second, AttemptTimeout: 45 * time.Second},
+		},
+		{
+			"all set", RetryPolicy{MaxAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Minute, AttemptTimeout: time.Minute},
+			RetryPolicy{MaxAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Minute, AttemptTimeout: time.Minute},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.policy.withDefaults(); got != test.want {
+				t.Errorf("withDefaults() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
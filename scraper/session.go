@@ -0,0 +1,49 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+)
+
+// session is the state of a scrape run shared by all of its tabs. It's kept in the run's
+// context, so runs at the same time don't share their rate limits, proxies or failures.
+type session struct {
+	rateLimiter  *RateLimiter
+	retryPolicy  RetryPolicy
+	fingerprints *fingerprintRotation
+	proxies      *proxyPool
+	failures     *failureList
+}
+
+type sessionKey struct{}
+
+var defaultSession struct {
+	once    sync.Once
+	session *session
+}
+
+func newSession(opts ScrapeOptions) *session {
+	return &session{
+		rateLimiter:  NewRateLimiter(DEFAULT_RATE_LIMIT, opts.RateLimits, opts.Jitter),
+		retryPolicy:  opts.Retry.withDefaults(),
+		fingerprints: newFingerprintRotation(opts.Fingerprints, opts.RotateEvery),
+		proxies:      newProxyPool(opts.Proxies),
+		failures:     &failureList{},
+	}
+}
+
+func withSession(ctx context.Context, sess *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// getSession gets the session of the run. The sources' helpers can also be used outside
+// of Scrape, then they share the default session.
+func getSession(ctx context.Context) *session {
+	if sess, ok := ctx.Value(sessionKey{}).(*session); ok {
+		return sess
+	}
+	defaultSession.once.Do(func() {
+		defaultSession.session = newSession(ScrapeOptions{Jitter: DEFAULT_JITTER})
+	})
+	return defaultSession.session
+}
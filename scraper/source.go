@@ -0,0 +1,297 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default number of detail pages saved each run for development phase
+const DEFAULT_SCRAPE_LIMIT int = 50
+
+// Query of the objects to scrape from a source
+type Query struct {
+	City    string
+	Keyword string
+	// Maximum number of detail pages to save, 0 means no limit
+	Limit int
+}
+
+var NON_SLUG_REGEX = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CityDir gets the directory name of the query's city, e.g. "plano-tx" for "Plano, TX"
+func (query Query) CityDir() string {
+	return strings.Trim(NON_SLUG_REGEX.ReplaceAllString(strings.ToLower(query.City), "-"), "-")
+}
+
+// Source is a site the objects are scraped from. The detail pages are saved as HTML
+// to <data dir>/<object type>/<city>/ and parsed offline.
+type Source interface {
+	// Name of the source, e.g. "redfin"
+	Name() string
+	// ObjectType is the type of the scraped objects, e.g. "house"
+	ObjectType() string
+	// ListLinks gets the links to the detail pages of the objects matching the query
+	ListLinks(cdpCtx context.Context, query Query) ([]string, error)
+	// DetailName gets the file name, without extension, of the detail page of the link
+	DetailName(link string) string
+	// FetchDetail navigates to the detail page and gets the HTML to be saved
+	FetchDetail(cdpCtx context.Context, link string) ([]byte, error)
+	// Parse parses all of the HTML saved under the directory to the objects, e.g. []*object.HomeInfo.
+	// The objects are returned with a *PartialError when some of the files are skipped.
+	Parse(ctx context.Context, dirName string) (any, error)
+}
+
+// SettingsSource is a source with settings narrowing its search, e.g. Redfin's filters.
+// The settings are recorded as settings.json alongside the saved pages, so the data set
+// can be reproduced.
+type SettingsSource interface {
+	Source
+	// Settings of the search, which are marshalled to JSON. It's nil for the default search.
+	Settings() any
+}
+
+var sources = map[string]Source{}
+
+// RegisterSource makes the source available to the CLI, it's called in the init of the source's file
+func RegisterSource(src Source) {
+	if _, exists := sources[src.Name()]; exists {
+		panic(fmt.Sprintf("Source %s is registered twice", src.Name()))
+	}
+	sources[src.Name()] = src
+}
+
+// GetSource gets the source by its name
+func GetSource(name string) (Source, error) {
+	src, exists := sources[name]
+	if !exists {
+		return nil, fmt.Errorf("Source %s is not supported", name)
+	}
+	return src, nil
+}
+
+// FindSource gets the first source, by name, scraping the object type
+func FindSource(objectType string) (Source, error) {
+	for _, src := range ListSources() {
+		if src.ObjectType() == objectType {
+			return src, nil
+		}
+	}
+	return nil, fmt.Errorf("Object %s is not supported by any source", objectType)
+}
+
+// ListSources gets all of the registered sources sorted by name
+func ListSources() []Source {
+	var srcList []Source
+	for _, src := range sources {
+		srcList = append(srcList, src)
+	}
+	sort.Slice(srcList, func(i, j int) bool {
+		return srcList[i].Name() < srcList[j].Name()
+	})
+	return srcList
+}
+
+// Options of how the objects are scraped
+type ScrapeOptions struct {
+	// Directory the detail pages are saved to, as <DataDir>/<object type>/<city>/<name>.html.
+	// The pages already saved there are skipped. The pages aren't saved if it's empty.
+	DataDir string
+	// Number of tabs fetching the detail pages at the same time
+	Concurrency int
+	// Rate limits by domain, the others use DEFAULT_RATE_LIMIT
+	RateLimits map[string]RateLimit
+	// Maximum random delay added to every navigation
+	Jitter time.Duration
+	// Retry policy of the navigations and the selector waits, DEFAULT_RETRY_POLICY if empty
+	Retry RetryPolicy
+	// Browser fingerprints handed out to the tabs, DEFAULT_FINGERPRINTS if empty
+	Fingerprints []Fingerprint
+	// Number of navigations before a tab switches fingerprint, 0 keeps it for the whole session
+	RotateEvery int
+	// Proxies handed out to the tabs, the tabs connect directly if empty
+	Proxies []Proxy
+	// Channel the detail pages are also sent to as soon as they're fetched, if it's not nil.
+	// It's closed when the run ends.
+	PageChan chan<- Page
+	// Start over instead of resuming the unfinished run of the query
+	Fresh bool
+}
+
+// Page is a detail page fetched by the run
+type Page struct {
+	Link string
+	Name string
+	// Path of the saved HTML, empty if the pages aren't saved
+	Path string
+	HTML []byte
+}
+
+// Result of a scrape run
+type Result struct {
+	Pages    []Page
+	Failures []Failure
+	// Links which weren't fetched because the run was interrupted
+	Remaining []string
+}
+
+// runStatePath gets the path of the query's run state, it's empty if the pages aren't saved
+func runStatePath(src Source, query Query, opts ScrapeOptions) string {
+	if opts.DataDir == "" {
+		return ""
+	}
+	return filepath.Join(opts.DataDir, "runs", fmt.Sprintf("%s_%s.json", src.ObjectType(), query.CityDir()))
+}
+
+// Scrape scrapes the detail pages matching the query from the source. The failed pages are
+// reported, so they can be scraped again in the next run. A *PartialError is returned
+// with the result when some of the pages failed.
+//
+// The progress of the run is saved to <DataDir>/runs as it goes and the file is removed
+// when the run is finished. An interrupted or crashed run is resumed by the next run of the
+// query, which fetches its unsaved links without crawling the search pages again. When the
+// context is canceled, the pages being fetched are left for the next run, the browser is
+// closed and the result so far is returned with an error wrapping context.Canceled.
+func Scrape(ctx context.Context, src Source, query Query, opts ScrapeOptions) (*Result, error) {
+	if opts.PageChan != nil {
+		defer close(opts.PageChan)
+	}
+	sess := newSession(opts)
+	defer sess.proxies.printReport()
+	ctx = withSession(ctx, sess)
+
+	pool := &detailPool{src: src, limit: query.Limit, pageChan: opts.PageChan}
+	if opts.DataDir != "" {
+		// Create the non-existent city directory
+		pool.dirName = filepath.Join(opts.DataDir, src.ObjectType(), query.CityDir())
+		err := os.MkdirAll(pool.dirName, 0755)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create city dir\n%w", err)
+		}
+		if err = recordSettings(src, pool.dirName); err != nil {
+			return nil, err
+		}
+	}
+
+	state := newRunState(runStatePath(src, query, opts), src, query)
+	if !opts.Fresh {
+		var err error
+		if state, err = loadRunState(state.path, src, query); err != nil {
+			return nil, err
+		}
+	}
+
+	cdpCtx, cdpCancel, err := newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cdpCancel()
+
+	if state.discovered() {
+		fmt.Printf(
+			"Resuming the run started at %s, %d of %d links left\n",
+			state.state.StartedAt.Format(time.DateTime), len(state.unsaved()), len(state.state.Links),
+		)
+	} else {
+		links, err := src.ListLinks(cdpCtx, query)
+		if err != nil && ctx.Err() != nil {
+			return nil, fmt.Errorf("Interrupted before fetching any %s\n%w", src.ObjectType(), ctx.Err())
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed to fetch links to all of the %ss\n%w", src.ObjectType(), err)
+		}
+		if err = state.setLinks(links); err != nil {
+			return nil, fmt.Errorf("Failed to save run state\n%w", err)
+		}
+	}
+
+	pool.state = state
+
+	fmt.Printf("Saving %s infos of %s with %d tabs...\n", src.ObjectType(), query.City, opts.Concurrency)
+	// The failed links of the previous run are fetched again too
+	if err = pool.run(cdpCtx, state.unsaved(), opts.Concurrency); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf(
+		"Saved %d %s infos of %s successfully, %d failed\n",
+		len(pool.pages), src.ObjectType(), query.City, pool.failed,
+	)
+	result := &Result{Pages: pool.pages, Failures: sess.failures.all()}
+	if opts.DataDir != "" {
+		failuresPath := filepath.Join(opts.DataDir, "failures", fmt.Sprintf("%s_%s.json", src.ObjectType(), query.CityDir()))
+		if err = writeFailures(result.Failures, failuresPath); err != nil {
+			return result, err
+		}
+	}
+
+	if ctx.Err() != nil {
+		result.Remaining = state.pending()
+		if err = state.flush(); err != nil {
+			return result, fmt.Errorf("Failed to save run state\n%w", err)
+		}
+		if state.path != "" {
+			fmt.Printf("Interrupted with %d links remaining, see %s\n", len(result.Remaining), state.path)
+		}
+		return result, fmt.Errorf(
+			"Interrupted after saving %d %s infos\n%w", len(result.Pages), src.ObjectType(), ctx.Err(),
+		)
+	}
+	if err = state.remove(); err != nil {
+		return result, fmt.Errorf("Failed to remove run state\n%w", err)
+	}
+
+	var errs []error
+	for _, fail := range result.Failures {
+		errs = append(errs, fail.Err)
+	}
+	return result, NewPartialError(len(result.Pages), errs)
+}
+
+// recordSettings records the settings of the source in the directory of the saved pages.
+// The pages saved with other settings aren't mixed with the new ones.
+func recordSettings(src Source, dirName string) error {
+	settingsSrc, ok := src.(SettingsSource)
+	if !ok {
+		return nil
+	}
+	settings := settingsSrc.Settings()
+	jsonData, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	settingsPath := filepath.Join(dirName, "settings.json")
+	prevData, err := os.ReadFile(settingsPath)
+	if err == nil && (settings == nil || !bytes.Equal(prevData, jsonData)) {
+		return fmt.Errorf(
+			"The pages in %s are saved with other settings, see %s. Move them or use another data dir, e.g. with -data-dir",
+			dirName, settingsPath,
+		)
+	} else if err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	} else if settings == nil {
+		return nil
+	}
+
+	// The pages saved before the settings are recorded are from the default search
+	entries, err := os.ReadDir(dirName)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf(
+			"The pages in %s are saved from the default search. Move them or use another data dir, e.g. with -data-dir", dirName,
+		)
+	}
+	return WriteFileAtomic(settingsPath, jsonData)
+}
@@ -0,0 +1,203 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Status of a detail page's link in the run
+type LinkStatus string
+
+const (
+	LINK_PENDING LinkStatus = "pending"
+	LINK_SAVED   LinkStatus = "saved"
+	LINK_FAILED  LinkStatus = "failed"
+)
+
+// LinkState is the progress of a detail page's link
+type LinkState struct {
+	Link   string     `json:"link"`
+	Status LinkStatus `json:"status"`
+	// Number of times the page was fetched, each with the attempts of the retry policy
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// RunState is the progress of a run. It's saved as the run goes, so an interrupted
+// or crashed run resumes where it stopped without crawling the search pages again.
+type RunState struct {
+	Source    string    `json:"source"`
+	City      string    `json:"city"`
+	Keyword   string    `json:"keyword,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Links of the detail pages found on the search pages, nil until the search pages are crawled
+	Links []*LinkState `json:"links"`
+}
+
+// runState is the state of the run saved to its file, it's only kept in memory if the path is empty
+type runState struct {
+	mu    sync.Mutex
+	path  string
+	state RunState
+	index map[string]*LinkState
+}
+
+// newRunState starts the state of a new run of the query
+func newRunState(path string, src Source, query Query) *runState {
+	return &runState{
+		path: path,
+		state: RunState{
+			Source:    src.Name(),
+			City:      query.City,
+			Keyword:   query.Keyword,
+			StartedAt: time.Now(),
+		},
+	}
+}
+
+// loadRunState loads the state of the previous run of the query, which isn't finished yet.
+// A new state is started when there's none or it's of another source or keyword.
+func loadRunState(path string, src Source, query Query) (*runState, error) {
+	rs := newRunState(path, src, query)
+	if path == "" {
+		return rs, nil
+	}
+
+	jsonData, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rs, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to read run state %s\n%w", path, err)
+	}
+	var prevState RunState
+	if err = json.Unmarshal(jsonData, &prevState); err != nil {
+		return nil, fmt.Errorf("Failed to read run state %s\n%w", path, err)
+	}
+	if prevState.Source != src.Name() || prevState.Keyword != query.Keyword {
+		fmt.Printf("Run state %s is of another query, starting over\n", path)
+		return rs, nil
+	}
+
+	rs.state = prevState
+	rs.indexLinks()
+	return rs, nil
+}
+
+func (rs *runState) indexLinks() {
+	rs.index = make(map[string]*LinkState, len(rs.state.Links))
+	for _, linkState := range rs.state.Links {
+		rs.index[linkState.Link] = linkState
+	}
+}
+
+// discovered checks if the links of the run are already found
+func (rs *runState) discovered() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.state.Links != nil
+}
+
+// setLinks sets the links found on the search pages and saves the state
+func (rs *runState) setLinks(links []string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.state.Links = make([]*LinkState, 0, len(links))
+	for _, link := range links {
+		rs.state.Links = append(rs.state.Links, &LinkState{Link: link, Status: LINK_PENDING})
+	}
+	rs.indexLinks()
+	return rs.save()
+}
+
+// unsaved gets the links which aren't saved yet, in the order they're found
+func (rs *runState) unsaved() []string {
+	return rs.linksWhere(func(linkState *LinkState) bool {
+		return linkState.Status != LINK_SAVED
+	})
+}
+
+// pending gets the links which aren't fetched yet
+func (rs *runState) pending() []string {
+	return rs.linksWhere(func(linkState *LinkState) bool {
+		return linkState.Status == LINK_PENDING
+	})
+}
+
+func (rs *runState) linksWhere(match func(linkState *LinkState) bool) []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	var links []string
+	for _, linkState := range rs.state.Links {
+		if match(linkState) {
+			links = append(links, linkState.Link)
+		}
+	}
+	return links
+}
+
+// attempt counts the fetch of the link
+func (rs *runState) attempt(link string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if linkState, exists := rs.index[link]; exists {
+		linkState.Attempts += 1
+	}
+}
+
+// finish sets the status of the link, with the error if it failed, and saves the state
+func (rs *runState) finish(link string, status LinkStatus, err error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	linkState, exists := rs.index[link]
+	if !exists {
+		return
+	}
+	linkState.Status = status
+	if err != nil {
+		linkState.LastError = err.Error()
+	}
+	if err = rs.save(); err != nil {
+		fmt.Printf("Failed to save run state %s\n%s\n", rs.path, err)
+	}
+}
+
+// save writes the state to its file, the lock must be held
+func (rs *runState) save() error {
+	rs.state.UpdatedAt = time.Now()
+	if rs.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(rs.path), 0755); err != nil {
+		return err
+	}
+	jsonData, err := json.MarshalIndent(rs.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(rs.path, jsonData)
+}
+
+// flush saves the state of the interrupted run
+func (rs *runState) flush() error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.save()
+}
+
+// remove removes the state file of the finished run, so the next run starts over
+func (rs *runState) remove() error {
+	if rs.path == "" {
+		return nil
+	}
+	err := os.Remove(rs.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type stubSource struct {
+	name string
+}
+
+func (src stubSource) Name() string       { return src.name }
+func (src stubSource) ObjectType() string { return "stub" }
+func (src stubSource) ListLinks(cdpCtx context.Context, query Query) ([]string, error) {
+	return nil, nil
+}
+func (src stubSource) DetailName(link string) string { return filepath.Base(link) }
+func (src stubSource) FetchDetail(cdpCtx context.Context, link string) ([]byte, error) {
+	return nil, nil
+}
+func (src stubSource) Parse(ctx context.Context, dirName string) (any, error) { return nil, nil }
+
+func TestRunStateResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "run.json")
+	src := stubSource{name: "stub"}
+	query := Query{City: "richardson", Keyword: "homes"}
+
+	rs, err := loadRunState(path, src, query)
+	if err != nil {
+		t.Fatalf("loadRunState() error = %v", err)
+	}
+	if rs.discovered() {
+		t.Fatal("discovered() = true before the links are set")
+	}
+	if err = rs.setLinks([]string{"a", "b", "c"}); err != nil {
+		t.Fatalf("setLinks() error = %v", err)
+	}
+	rs.attempt("a")
+	rs.finish("a", LINK_SAVED, nil)
+	rs.attempt("b")
+	rs.finish("b", LINK_FAILED, errors.New("timed out"))
+	rs.finish("unknown", LINK_SAVED, nil)
+
+	resumed, err := loadRunState(path, src, query)
+	if err != nil {
+		t.Fatalf("loadRunState() error = %v", err)
+	}
+	if !resumed.discovered() {
+		t.Fatal("discovered() = false after resuming")
+	}
+	if got, want := resumed.unsaved(), []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unsaved() = %v, want %v", got, want)
+	}
+	if got, want := resumed.pending(), []string{"c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pending() = %v, want %v", got, want)
+	}
+	failed := resumed.index["b"]
+	if failed.Attempts != 1 || failed.LastError != "timed out" {
+		t.Errorf("failed link = %+v, want 1 attempt with the error", failed)
+	}
+
+	// The failed link is fetched again and counts another attempt
+	resumed.attempt("b")
+	resumed.finish("b", LINK_SAVED, nil)
+	if got := resumed.unsaved(); len(got) != 1 || got[0] != "c" {
+		t.Errorf("unsaved() = %v, want [c]", got)
+	}
+	if resumed.index["b"].Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", resumed.index["b"].Attempts)
+	}
+
+	if err = resumed.remove(); err != nil {
+		t.Fatalf("remove() error = %v", err)
+	}
+	if _, err = os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("run state %s is still there after remove()", path)
+	}
+	if err = resumed.remove(); err != nil {
+		t.Errorf("remove() of the removed state error = %v", err)
+	}
+}
+
+func TestLoadRunStateOfAnotherQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   Source
+		query Query
+	}{
+		{"another source", stubSource{name: "other"}, Query{Keyword: "homes"}},
+		{"another keyword", stubSource{name: "stub"}, Query{Keyword: "condos"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "run.json")
+			rs := newRunState(path, stubSource{name: "stub"}, Query{Keyword: "homes"})
+			if err := rs.setLinks([]string{"a"}); err != nil {
+				t.Fatalf("setLinks() error = %v", err)
+			}
+
+			loaded, err := loadRunState(path, test.src, test.query)
+			if err != nil {
+				t.Fatalf("loadRunState() error = %v", err)
+			}
+			if loaded.discovered() {
+				t.Error("discovered() = true for the state of another query, want a new state")
+			}
+		})
+	}
+}
+
+func TestLoadRunStateCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadRunState(path, stubSource{name: "stub"}, Query{}); err == nil {
+		t.Error("loadRunState() of a corrupted state error = nil, want an error")
+	}
+}
+
+func TestRunStateInMemory(t *testing.T) {
+	rs, err := loadRunState("", stubSource{name: "stub"}, Query{})
+	if err != nil {
+		t.Fatalf("loadRunState() error = %v", err)
+	}
+	if err = rs.setLinks([]string{"a"}); err != nil {
+		t.Fatalf("setLinks() error = %v", err)
+	}
+	if err = rs.remove(); err != nil {
+		t.Errorf("remove() error = %v", err)
+	}
+}